@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"github.com/covexo/devspace/pkg/devspace/config/configutil"
+	"github.com/covexo/devspace/pkg/devspace/image"
+	"github.com/covexo/devspace/pkg/devspace/kubectl"
+	"github.com/covexo/devspace/pkg/util/log"
+	"github.com/spf13/cobra"
+)
+
+// BuildCmd holds the information needed for the build command
+type BuildCmd struct {
+	warmCacheFlags *buildWarmCacheCmdFlags
+}
+
+type buildWarmCacheCmdFlags struct {
+	switchContext   bool
+	config          string
+	configOverwrite string
+}
+
+func init() {
+	cmd := &BuildCmd{
+		warmCacheFlags: &buildWarmCacheCmdFlags{},
+	}
+
+	buildCmd := &cobra.Command{
+		Use:   "build",
+		Short: "Manages the image builds",
+		Long: `
+	#######################################################
+	################### devspace build ####################
+	#######################################################
+	Manages the image builds:
+
+	* Pull base images into the kaniko cache (warm-cache)
+	#######################################################
+	`,
+		Args: cobra.NoArgs,
+	}
+
+	rootCmd.AddCommand(buildCmd)
+
+	buildWarmCacheCmd := &cobra.Command{
+		Use:   "warm-cache",
+		Short: "Pulls the base images into the kaniko cache volumes",
+		Long: `
+	#######################################################
+	############# devspace build warm-cache ###############
+	#######################################################
+	Pulls the base images of all images that are built
+	with kaniko and a cacheVolumeClaim into the cache
+	volume, so that the build pods don't pull them for
+	every build:
+
+	devspace build warm-cache
+	#######################################################
+	`,
+		Args: cobra.NoArgs,
+		Run:  cmd.RunWarmCache,
+	}
+
+	buildWarmCacheCmd.Flags().BoolVar(&cmd.warmCacheFlags.switchContext, "switch-context", false, "Switch kubectl context to the devspace context")
+	buildWarmCacheCmd.Flags().StringVar(&cmd.warmCacheFlags.config, "config", configutil.ConfigPath, "The devspace config file to load (default: '.devspace/config.yaml'")
+	buildWarmCacheCmd.Flags().StringVar(&cmd.warmCacheFlags.configOverwrite, "config-overwrite", configutil.OverwriteConfigPath, "The devspace config overwrite file to load (default: '.devspace/overwrite.yaml'")
+
+	buildCmd.AddCommand(buildWarmCacheCmd)
+}
+
+// RunWarmCache executes the devspace build warm-cache command logic
+func (cmd *BuildCmd) RunWarmCache(cobraCmd *cobra.Command, args []string) {
+	if configutil.ConfigPath != cmd.warmCacheFlags.config {
+		configutil.ConfigPath = cmd.warmCacheFlags.config
+
+		// Don't use overwrite config if we use a different config
+		configutil.OverwriteConfigPath = ""
+	}
+	if configutil.OverwriteConfigPath != cmd.warmCacheFlags.configOverwrite {
+		configutil.OverwriteConfigPath = cmd.warmCacheFlags.configOverwrite
+	}
+
+	log.StartFileLogging()
+
+	client, err := kubectl.NewClientWithContextSwitch(cmd.warmCacheFlags.switchContext)
+	if err != nil {
+		log.Fatalf("Unable to create new kubectl client: %v", err)
+	}
+
+	err = image.WarmCache(client, log.GetInstance())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	log.Done("Successfully warmed the kaniko cache")
+}
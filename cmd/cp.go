@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"github.com/covexo/devspace/pkg/devspace/config/configutil"
+	"github.com/covexo/devspace/pkg/devspace/kubectl"
+	"github.com/covexo/devspace/pkg/devspace/services"
+	"github.com/covexo/devspace/pkg/util/log"
+	"github.com/spf13/cobra"
+)
+
+// CpCmd is a struct that defines a command call for "cp"
+type CpCmd struct {
+	flags *CpCmdFlags
+}
+
+// CpCmdFlags are the flags available for the cp-command
+type CpCmdFlags struct {
+	namespace       string
+	container       string
+	exclude         []string
+	pick            bool
+	switchContext   bool
+	config          string
+	configOverwrite string
+}
+
+func init() {
+	cmd := &CpCmd{
+		flags: &CpCmdFlags{},
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "cp [source] [destination]",
+		Short: "Copies files and directories to and from containers",
+		Long: `
+#######################################################
+##################### devspace cp #####################
+#######################################################
+Copies files and directories between the local
+filesystem and a container. Container paths start with
+service:[name:] (default: terminal service) or
+pod:name:
+
+devspace cp ./fixtures service:/app/fixtures
+devspace cp service:my-service:/tmp/heap.hprof .
+devspace cp pod:my-pod-7f9c:/var/log ./logs -c nginx
+devspace cp ./src service:/app --exclude node_modules
+#######################################################`,
+		Args: cobra.ExactArgs(2),
+		Run:  cmd.Run,
+	}
+	rootCmd.AddCommand(cobraCmd)
+
+	cobraCmd.Flags().StringVarP(&cmd.flags.container, "container", "c", "", "Container name within pod to copy from or to")
+	cobraCmd.Flags().StringVarP(&cmd.flags.namespace, "namespace", "n", "", "Namespace where to select pods")
+	cobraCmd.Flags().StringSliceVar(&cmd.flags.exclude, "exclude", []string{}, "Paths to exclude (.gitignore syntax, relative to the copied directory)")
+	cobraCmd.Flags().BoolVar(&cmd.flags.pick, "pick", false, "Select the pod and container interactively")
+	cobraCmd.Flags().BoolVar(&cmd.flags.switchContext, "switch-context", false, "Switch kubectl context to the devspace context")
+	cobraCmd.Flags().StringVar(&cmd.flags.config, "config", configutil.ConfigPath, "The devspace config file to load (default: '.devspace/config.yaml'")
+	cobraCmd.Flags().StringVar(&cmd.flags.configOverwrite, "config-overwrite", configutil.OverwriteConfigPath, "The devspace config overwrite file to load (default: '.devspace/overwrite.yaml'")
+}
+
+// Run executes the command logic
+func (cmd *CpCmd) Run(cobraCmd *cobra.Command, args []string) {
+	if configutil.ConfigPath != cmd.flags.config {
+		configutil.ConfigPath = cmd.flags.config
+
+		// Don't use overwrite config if we use a different config
+		configutil.OverwriteConfigPath = ""
+	}
+	if configutil.OverwriteConfigPath != cmd.flags.configOverwrite {
+		configutil.OverwriteConfigPath = cmd.flags.configOverwrite
+	}
+
+	log.StartFileLogging()
+
+	kubectl, err := kubectl.NewClientWithContextSwitch(cmd.flags.switchContext)
+	if err != nil {
+		log.Fatalf("Unable to create new kubectl client: %v", err)
+	}
+
+	err = services.Copy(kubectl, args[0], args[1], cmd.flags.container, cmd.flags.namespace, cmd.flags.exclude, cmd.flags.pick, log.GetInstance())
+	if err != nil {
+		log.Fatal(err)
+	}
+}
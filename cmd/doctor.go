@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"os"
+	"strings"
+
+	"github.com/covexo/devspace/pkg/devspace/config/configutil"
+	"github.com/covexo/devspace/pkg/devspace/doctor"
+	"github.com/covexo/devspace/pkg/util/log"
+	"github.com/spf13/cobra"
+)
+
+// DoctorCmd is a struct that defines a command call for "doctor"
+type DoctorCmd struct {
+	flags *DoctorCmdFlags
+}
+
+// DoctorCmdFlags are the flags available for the doctor-command
+type DoctorCmdFlags struct {
+	config          string
+	configOverwrite string
+}
+
+func init() {
+	cmd := &DoctorCmd{
+		flags: &DoctorCmdFlags{},
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Checks your environment for common problems",
+		Long: `
+#######################################################
+################### devspace doctor ###################
+#######################################################
+Checks the config, the kube context, your permissions
+in the cluster, docker, minikube, registry credentials,
+tiller and the versions of the required tools and
+prints how to fix the problems that were found.
+
+Exits with exit code 1 if a check failed.
+#######################################################`,
+		Args: cobra.NoArgs,
+		Run:  cmd.Run,
+	}
+	rootCmd.AddCommand(cobraCmd)
+
+	cobraCmd.Flags().StringVar(&cmd.flags.config, "config", configutil.ConfigPath, "The devspace config file to load (default: '.devspace/config.yaml'")
+	cobraCmd.Flags().StringVar(&cmd.flags.configOverwrite, "config-overwrite", configutil.OverwriteConfigPath, "The devspace config overwrite file to load (default: '.devspace/overwrite.yaml'")
+}
+
+// Run executes the command logic
+func (cmd *DoctorCmd) Run(cobraCmd *cobra.Command, args []string) {
+	if configutil.ConfigPath != cmd.flags.config {
+		configutil.ConfigPath = cmd.flags.config
+
+		// Don't use overwrite config if we use a different config
+		configutil.OverwriteConfigPath = ""
+	}
+	if configutil.OverwriteConfigPath != cmd.flags.configOverwrite {
+		configutil.OverwriteConfigPath = cmd.flags.configOverwrite
+	}
+
+	log.StartFileLogging()
+
+	results := doctor.Run(log.GetInstance())
+
+	values := make([][]string, 0, len(results))
+	for _, result := range results {
+		values = append(values, []string{
+			result.Check,
+			result.Status,
+			// Multi-line errors would break the table
+			strings.Join(strings.Fields(result.Info), " "),
+			result.Hint,
+		})
+	}
+
+	log.PrintTable([]string{
+		"CHECK",
+		"STATUS",
+		"INFO",
+		"HOW TO FIX",
+	}, values)
+
+	if doctor.HasFailed(results) {
+		os.Exit(1)
+	}
+}
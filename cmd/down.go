@@ -8,6 +8,7 @@ import (
 	deployHelm "github.com/covexo/devspace/pkg/devspace/deploy/helm"
 	deployKubectl "github.com/covexo/devspace/pkg/devspace/deploy/kubectl"
 	"github.com/covexo/devspace/pkg/devspace/kubectl"
+	"github.com/covexo/devspace/pkg/devspace/services"
 	"github.com/covexo/devspace/pkg/util/log"
 	"k8s.io/client-go/kubernetes"
 
@@ -90,6 +91,11 @@ func deleteDevSpace(kubectl *kubernetes.Clientset, deployments []string) {
 		deployments = nil
 	}
 
+	// Restore the original workloads of services in dev mode
+	if deployments == nil {
+		services.StopDevMode(kubectl, log.GetInstance())
+	}
+
 	if config.DevSpace.Deployments != nil {
 		for _, deployConfig := range *config.DevSpace.Deployments {
 			// Check if we should skip deleting deployment
@@ -1,6 +1,9 @@
 package cmd
 
 import (
+	"os"
+	"strings"
+
 	"github.com/covexo/devspace/pkg/devspace/config/configutil"
 	"github.com/covexo/devspace/pkg/devspace/kubectl"
 	"github.com/covexo/devspace/pkg/devspace/services"
@@ -19,6 +22,13 @@ type EnterCmdFlags struct {
 	namespace       string
 	labelSelector   string
 	container       string
+	workDir         string
+	env             []string
+	user            string
+	pick            bool
+	record          string
+	debug           bool
+	image           string
 	switchContext   bool
 	config          string
 	configOverwrite string
@@ -43,6 +53,12 @@ devspace enter
 devspace enter bash
 devspace enter -s my-service
 devspace enter -c my-container
+devspace enter --pick
+devspace enter --workdir /app -e DEBUG=true -e GITHUB_TOKEN
+devspace enter --user root
+devspace enter --record session.cast
+devspace enter --debug
+devspace enter --debug --image alpine -c my-container
 devspace enter bash -n my-namespace
 devspace enter bash -l release=test
 #######################################################`,
@@ -54,6 +70,13 @@ devspace enter bash -l release=test
 	cobraCmd.Flags().StringVarP(&cmd.flags.container, "container", "c", "", "Container name within pod where to execute command")
 	cobraCmd.Flags().StringVarP(&cmd.flags.labelSelector, "label-selector", "l", "", "Comma separated key=value selector list (e.g. release=test)")
 	cobraCmd.Flags().StringVarP(&cmd.flags.namespace, "namespace", "n", "", "Namespace where to select pods")
+	cobraCmd.Flags().StringVar(&cmd.flags.workDir, "workdir", "", "Working directory for the command within the container")
+	cobraCmd.Flags().StringSliceVarP(&cmd.flags.env, "env", "e", []string{}, "Environment variables for the command (KEY=VALUE, or KEY to use the value of the local environment variable)")
+	cobraCmd.Flags().StringVar(&cmd.flags.user, "user", "", "User to run the command as (requires su in the container)")
+	cobraCmd.Flags().BoolVar(&cmd.flags.pick, "pick", false, "Select the pod and container interactively")
+	cobraCmd.Flags().StringVar(&cmd.flags.record, "record", "", "Record the terminal session to this file (asciicast v2 format)")
+	cobraCmd.Flags().BoolVar(&cmd.flags.debug, "debug", false, "Start a debug container that shares the process namespace of the selected container (useful for images without a shell)")
+	cobraCmd.Flags().StringVar(&cmd.flags.image, "image", services.DefaultDebugImage, "The image to use for the debug container")
 	cobraCmd.Flags().BoolVar(&cmd.flags.switchContext, "switch-context", true, "Switch kubectl context to the devspace context")
 	cobraCmd.Flags().StringVar(&cmd.flags.config, "config", configutil.ConfigPath, "The devspace config file to load (default: '.devspace/config.yaml'")
 	cobraCmd.Flags().StringVar(&cmd.flags.configOverwrite, "config-overwrite", configutil.OverwriteConfigPath, "The devspace config overwrite file to load (default: '.devspace/overwrite.yaml'")
@@ -79,8 +102,40 @@ func (cmd *EnterCmd) Run(cobraCmd *cobra.Command, args []string) {
 		log.Fatalf("Unable to create new kubectl client: %v", err)
 	}
 
-	err = services.StartTerminal(kubectl, cmd.flags.service, cmd.flags.container, cmd.flags.labelSelector, cmd.flags.namespace, args, make(chan error), log.GetInstance())
+	if cmd.flags.debug {
+		if cmd.flags.record != "" {
+			log.Warn("Recording is not supported for debug containers, --record will be ignored")
+		}
+
+		err = services.StartDebugTerminal(kubectl, cmd.flags.service, cmd.flags.container, cmd.flags.labelSelector, cmd.flags.namespace, cmd.flags.image, args, cmd.flags.pick, log.GetInstance())
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		return
+	}
+
+	err = services.StartTerminal(kubectl, cmd.flags.service, cmd.flags.container, cmd.flags.labelSelector, cmd.flags.namespace, args, &services.TerminalOptions{
+		WorkDir: cmd.flags.workDir,
+		Env:     parseEnvFlags(cmd.flags.env),
+		User:    cmd.flags.user,
+	}, cmd.flags.pick, cmd.flags.record, make(chan error), log.GetInstance())
 	if err != nil {
 		log.Fatal(err)
 	}
 }
+
+// parseEnvFlags parses KEY=VALUE environment flags. If only KEY is given, the value of the local environment variable is used
+func parseEnvFlags(flags []string) map[string]string {
+	env := map[string]string{}
+	for _, variable := range flags {
+		splitted := strings.SplitN(variable, "=", 2)
+		if len(splitted) == 2 {
+			env[splitted[0]] = splitted[1]
+		} else {
+			env[splitted[0]] = os.Getenv(splitted[0])
+		}
+	}
+
+	return env
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/covexo/devspace/pkg/devspace/config/configutil"
+	"github.com/covexo/devspace/pkg/devspace/kubectl"
+	"github.com/covexo/devspace/pkg/devspace/services"
+	"github.com/covexo/devspace/pkg/util/log"
+	"github.com/spf13/cobra"
+)
+
+// ExecCmd is a struct that defines a command call for "exec"
+type ExecCmd struct {
+	flags *ExecCmdFlags
+}
+
+// ExecCmdFlags are the flags available for the exec-command
+type ExecCmdFlags struct {
+	service         string
+	namespace       string
+	labelSelector   string
+	container       string
+	tty             bool
+	stdin           bool
+	workDir         string
+	env             []string
+	user            string
+	pick            bool
+	switchContext   bool
+	config          string
+	configOverwrite string
+}
+
+func init() {
+	cmd := &ExecCmd{
+		flags: &ExecCmdFlags{},
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "exec [command] [args]",
+		Short: "Executes a command in the devspace",
+		Long: `
+#######################################################
+################### devspace exec #####################
+#######################################################
+Executes a command non-interactively in the devspace
+and exits with the exit code of the command. Stdin is
+passed to the command if it is piped into devspace:
+
+devspace exec npm test
+devspace exec -s my-service ls -la /app
+devspace exec -e CI=true --workdir /app npm test
+cat dump.sql | devspace exec -c db -- psql -U postgres
+devspace exec -it sh
+#######################################################`,
+		Args: cobra.MinimumNArgs(1),
+		Run:  cmd.Run,
+	}
+	rootCmd.AddCommand(cobraCmd)
+
+	// Flags after the command belong to the command (e.g. devspace exec ls -la)
+	cobraCmd.Flags().SetInterspersed(false)
+
+	cobraCmd.Flags().StringVarP(&cmd.flags.service, "service", "s", "", "Service name (in config) to select pod/container")
+	cobraCmd.Flags().StringVarP(&cmd.flags.container, "container", "c", "", "Container name within pod where to execute command")
+	cobraCmd.Flags().StringVarP(&cmd.flags.labelSelector, "label-selector", "l", "", "Comma separated key=value selector list (e.g. release=test)")
+	cobraCmd.Flags().StringVarP(&cmd.flags.namespace, "namespace", "n", "", "Namespace where to select pods")
+	cobraCmd.Flags().BoolVarP(&cmd.flags.tty, "tty", "t", false, "Allocate a TTY for the command")
+	cobraCmd.Flags().BoolVarP(&cmd.flags.stdin, "stdin", "i", false, "Pass stdin to the command even if it is a terminal")
+	cobraCmd.Flags().StringVar(&cmd.flags.workDir, "workdir", "", "Working directory for the command within the container")
+	cobraCmd.Flags().StringSliceVarP(&cmd.flags.env, "env", "e", []string{}, "Environment variables for the command (KEY=VALUE, or KEY to use the value of the local environment variable)")
+	cobraCmd.Flags().StringVar(&cmd.flags.user, "user", "", "User to run the command as (requires su in the container)")
+	cobraCmd.Flags().BoolVar(&cmd.flags.pick, "pick", false, "Select the pod and container interactively")
+	cobraCmd.Flags().BoolVar(&cmd.flags.switchContext, "switch-context", false, "Switch kubectl context to the devspace context")
+	cobraCmd.Flags().StringVar(&cmd.flags.config, "config", configutil.ConfigPath, "The devspace config file to load (default: '.devspace/config.yaml'")
+	cobraCmd.Flags().StringVar(&cmd.flags.configOverwrite, "config-overwrite", configutil.OverwriteConfigPath, "The devspace config overwrite file to load (default: '.devspace/overwrite.yaml'")
+}
+
+// Run executes the command logic
+func (cmd *ExecCmd) Run(cobraCmd *cobra.Command, args []string) {
+	if configutil.ConfigPath != cmd.flags.config {
+		configutil.ConfigPath = cmd.flags.config
+
+		// Don't use overwrite config if we use a different config
+		configutil.OverwriteConfigPath = ""
+	}
+	if configutil.OverwriteConfigPath != cmd.flags.configOverwrite {
+		configutil.OverwriteConfigPath = cmd.flags.configOverwrite
+	}
+
+	log.StartFileLogging()
+
+	kubectl, err := kubectl.NewClientWithContextSwitch(cmd.flags.switchContext)
+	if err != nil {
+		log.Fatalf("Unable to create new kubectl client: %v", err)
+	}
+
+	exitCode, err := services.ExecCommand(kubectl, cmd.flags.service, cmd.flags.container, cmd.flags.labelSelector, cmd.flags.namespace, args, &services.ExecOptions{
+		TerminalOptions: services.TerminalOptions{
+			WorkDir: cmd.flags.workDir,
+			Env:     parseEnvFlags(cmd.flags.env),
+			User:    cmd.flags.user,
+		},
+		TTY:   cmd.flags.tty,
+		Stdin: cmd.flags.stdin,
+	}, cmd.flags.pick, log.GetInstance())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	os.Exit(exitCode)
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/covexo/devspace/pkg/devspace/config/configutil"
+	"github.com/covexo/devspace/pkg/devspace/kubectl"
+	"github.com/covexo/devspace/pkg/devspace/services"
+	"github.com/covexo/devspace/pkg/util/log"
+	"github.com/spf13/cobra"
+)
+
+// LogsCmd is a struct that defines a command call for "logs"
+type LogsCmd struct {
+	flags *LogsCmdFlags
+}
+
+// LogsCmdFlags are the flags available for the logs-command
+type LogsCmdFlags struct {
+	service         string
+	namespace       string
+	labelSelector   string
+	container       string
+	follow          bool
+	previous        bool
+	pick            bool
+	since           time.Duration
+	tail            int64
+	switchContext   bool
+	config          string
+	configOverwrite string
+}
+
+func init() {
+	cmd := &LogsCmd{
+		flags: &LogsCmdFlags{},
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "logs",
+		Short: "Prints the logs of the devspace pods",
+		Long: `
+#######################################################
+################### devspace logs #####################
+#######################################################
+Prints the logs of all pods and containers that match
+the selected service or label selector:
+
+devspace logs
+devspace logs -f
+devspace logs -s my-service --tail 100
+devspace logs -c my-container --since 10m
+devspace logs -l release=test -n my-namespace
+devspace logs --previous
+#######################################################`,
+		Args: cobra.NoArgs,
+		Run:  cmd.Run,
+	}
+	rootCmd.AddCommand(cobraCmd)
+
+	cobraCmd.Flags().StringVarP(&cmd.flags.service, "service", "s", "", "Service name (in config) to select pods")
+	cobraCmd.Flags().StringVarP(&cmd.flags.container, "container", "c", "", "Only print the logs of this container (default: all containers)")
+	cobraCmd.Flags().StringVarP(&cmd.flags.labelSelector, "label-selector", "l", "", "Comma separated key=value selector list (e.g. release=test)")
+	cobraCmd.Flags().StringVarP(&cmd.flags.namespace, "namespace", "n", "", "Namespace where to select pods")
+	cobraCmd.Flags().BoolVarP(&cmd.flags.follow, "follow", "f", false, "Keep streaming the logs and pick up new pods as they appear")
+	cobraCmd.Flags().BoolVar(&cmd.flags.pick, "pick", false, "Select the pod and container interactively and only print its logs")
+	cobraCmd.Flags().BoolVarP(&cmd.flags.previous, "previous", "p", false, "Print the logs of the previous instance of crashed or restarted containers")
+	cobraCmd.Flags().DurationVar(&cmd.flags.since, "since", 0, "Only print logs newer than a relative duration (e.g. 10s, 5m, 1h)")
+	cobraCmd.Flags().Int64Var(&cmd.flags.tail, "tail", -1, "Number of lines to print from the end of the logs (default: all lines)")
+	cobraCmd.Flags().BoolVar(&cmd.flags.switchContext, "switch-context", false, "Switch kubectl context to the devspace context")
+	cobraCmd.Flags().StringVar(&cmd.flags.config, "config", configutil.ConfigPath, "The devspace config file to load (default: '.devspace/config.yaml'")
+	cobraCmd.Flags().StringVar(&cmd.flags.configOverwrite, "config-overwrite", configutil.OverwriteConfigPath, "The devspace config overwrite file to load (default: '.devspace/overwrite.yaml'")
+}
+
+// Run executes the command logic
+func (cmd *LogsCmd) Run(cobraCmd *cobra.Command, args []string) {
+	if configutil.ConfigPath != cmd.flags.config {
+		configutil.ConfigPath = cmd.flags.config
+
+		// Don't use overwrite config if we use a different config
+		configutil.OverwriteConfigPath = ""
+	}
+	if configutil.OverwriteConfigPath != cmd.flags.configOverwrite {
+		configutil.OverwriteConfigPath = cmd.flags.configOverwrite
+	}
+
+	log.StartFileLogging()
+
+	kubectl, err := kubectl.NewClientWithContextSwitch(cmd.flags.switchContext)
+	if err != nil {
+		log.Fatalf("Unable to create new kubectl client: %v", err)
+	}
+
+	err = services.StartLogs(kubectl, cmd.flags.service, cmd.flags.container, cmd.flags.labelSelector, cmd.flags.namespace, &services.LogsOptions{
+		Follow:   cmd.flags.follow,
+		Previous: cmd.flags.previous,
+		Since:    cmd.flags.since,
+		Tail:     cmd.flags.tail,
+	}, cmd.flags.pick, log.GetInstance())
+	if err != nil {
+		log.Fatal(err)
+	}
+}
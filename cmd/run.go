@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/covexo/devspace/pkg/devspace/config/configutil"
+	"github.com/covexo/devspace/pkg/devspace/kubectl"
+	"github.com/covexo/devspace/pkg/devspace/services"
+	"github.com/covexo/devspace/pkg/util/log"
+	"github.com/spf13/cobra"
+)
+
+// RunCmd is a struct that defines a command call for "run"
+type RunCmd struct {
+	flags *RunCmdFlags
+}
+
+// RunCmdFlags are the flags available for the run-command
+type RunCmdFlags struct {
+	switchContext   bool
+	config          string
+	configOverwrite string
+}
+
+func init() {
+	cmd := &RunCmd{
+		flags: &RunCmdFlags{},
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "run [command] [args]",
+		Short: "Runs a configured command in the devspace",
+		Long: `
+#######################################################
+#################### devspace run #####################
+#######################################################
+Runs a command that is defined in the commands section
+of the config non-interactively in the devspace and
+exits with the exit code of the command:
+
+devspace run migrate
+devspace run test -- --verbose
+#######################################################`,
+		Args: cobra.MinimumNArgs(1),
+		Run:  cmd.Run,
+	}
+	rootCmd.AddCommand(cobraCmd)
+
+	cobraCmd.Flags().BoolVar(&cmd.flags.switchContext, "switch-context", false, "Switch kubectl context to the devspace context")
+	cobraCmd.Flags().StringVar(&cmd.flags.config, "config", configutil.ConfigPath, "The devspace config file to load (default: '.devspace/config.yaml'")
+	cobraCmd.Flags().StringVar(&cmd.flags.configOverwrite, "config-overwrite", configutil.OverwriteConfigPath, "The devspace config overwrite file to load (default: '.devspace/overwrite.yaml'")
+}
+
+// Run executes the command logic
+func (cmd *RunCmd) Run(cobraCmd *cobra.Command, args []string) {
+	if configutil.ConfigPath != cmd.flags.config {
+		configutil.ConfigPath = cmd.flags.config
+
+		// Don't use overwrite config if we use a different config
+		configutil.OverwriteConfigPath = ""
+	}
+	if configutil.OverwriteConfigPath != cmd.flags.configOverwrite {
+		configutil.OverwriteConfigPath = cmd.flags.configOverwrite
+	}
+
+	log.StartFileLogging()
+
+	kubectl, err := kubectl.NewClientWithContextSwitch(cmd.flags.switchContext)
+	if err != nil {
+		log.Fatalf("Unable to create new kubectl client: %v", err)
+	}
+
+	exitCode, err := services.RunCommand(kubectl, args[0], args[1:], log.GetInstance())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	os.Exit(exitCode)
+}
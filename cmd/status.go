@@ -11,8 +11,11 @@ import (
 	helmClient "github.com/covexo/devspace/pkg/devspace/helm"
 	"github.com/covexo/devspace/pkg/devspace/kubectl"
 	"github.com/covexo/devspace/pkg/devspace/registry"
+	"github.com/covexo/devspace/pkg/devspace/services"
 	"github.com/covexo/devspace/pkg/util/log"
 	"github.com/spf13/cobra"
+	k8sv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/client-go/kubernetes"
 )
 
@@ -73,6 +76,7 @@ func (cmd *StatusCmd) RunStatus(cobraCmd *cobra.Command, args []string) {
 		"STATUS",
 		"NAMESPACE",
 		"INFO",
+		"USAGE",
 	}
 	config := configutil.GetConfig()
 
@@ -92,7 +96,7 @@ func (cmd *StatusCmd) RunStatus(cobraCmd *cobra.Command, args []string) {
 				err.Error(),
 			})
 
-			log.PrintTable(headerValues, values)
+			log.PrintTable(headerValues, fillColumns(values, len(headerValues)))
 			return
 		}
 
@@ -135,11 +139,97 @@ func (cmd *StatusCmd) RunStatus(cobraCmd *cobra.Command, args []string) {
 				log.Warnf("Error retrieving status for deployment %s: %v", *deployConfig.Name, err)
 			}
 
+			// Helm charts label their pods with the release name
+			if deployConfig.Kubectl == nil && deployConfig.Namespace != nil {
+				usage := cmd.getReleaseUsage(*deployConfig.Name, *deployConfig.Namespace)
+				for index := range addValues {
+					addValues[index] = append(addValues[index], usage)
+				}
+			}
+
 			values = append(values, addValues...)
 		}
 	}
 
-	log.PrintTable(headerValues, values)
+	values = append(values, cmd.getPortProbeStatus()...)
+
+	log.PrintTable(headerValues, fillColumns(values, len(headerValues)))
+}
+
+// fillColumns adds empty values to rows that don't have a value for each column
+func fillColumns(values [][]string, columns int) [][]string {
+	for index := range values {
+		for len(values[index]) < columns {
+			values[index] = append(values[index], "")
+		}
+	}
+
+	return values
+}
+
+// getReleaseUsage returns the summed up cpu and memory usage of the pods of a helm release
+func (cmd *StatusCmd) getReleaseUsage(releaseName, namespace string) string {
+	usages, metricsAvailable, err := kubectl.GetContainerUsage(cmd.kubectl, "release="+releaseName, namespace)
+	if err != nil || len(usages) == 0 {
+		return ""
+	}
+	if metricsAvailable == false {
+		return "n/a (metrics-server not installed)"
+	}
+
+	cpu := resource.Quantity{}
+	memory := resource.Quantity{}
+	oomKilled := 0
+
+	for _, usage := range usages {
+		if usage.CPU != nil {
+			cpu.Add(*usage.CPU)
+		}
+		if usage.Memory != nil {
+			memory.Add(*usage.Memory)
+		}
+		if usage.LastTermination == "OOMKilled" {
+			oomKilled++
+		}
+	}
+
+	info := fmt.Sprintf("CPU: %s, Memory: %s", kubectl.FormatQuantity(&cpu, k8sv1.ResourceCPU), kubectl.FormatQuantity(&memory, k8sv1.ResourceMemory))
+	if oomKilled > 0 {
+		info += fmt.Sprintf(" (%d container(s) OOMKilled, see devspace top)", oomKilled)
+	}
+
+	return info
+}
+
+// getPortProbeStatus executes every port probe once. The probes of devspace up run in another process, so their tracked
+// state is not available here and a flapping port may show a different state than the transitions devspace up logs
+func (cmd *StatusCmd) getPortProbeStatus() [][]string {
+	values := [][]string{}
+
+	for _, probe := range services.GetPortProbes() {
+		status := "Healthy"
+		info := probe.Describe()
+
+		// Without devspace up nothing is forwarded, so the health of the service is unknown
+		if probe.IsListening() == false {
+			status = "Unknown"
+			info += ": port is not forwarded (devspace up is not running)"
+		} else if err := probe.Check(); err != nil {
+			status = "Unhealthy"
+			info += ": " + err.Error() + " (checked now)"
+		} else {
+			info += " (checked now)"
+		}
+
+		values = append(values, []string{
+			"Port " + probe.Name,
+			status,
+			"",
+			info,
+		})
+	}
+
+	return values
 }
 
 func (cmd *StatusCmd) getTillerStatus() ([]string, error) {
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"strconv"
+
+	"github.com/covexo/devspace/pkg/devspace/config/configutil"
+	"github.com/covexo/devspace/pkg/devspace/kubectl"
+	"github.com/covexo/devspace/pkg/devspace/services"
+	"github.com/covexo/devspace/pkg/util/log"
+	"github.com/spf13/cobra"
+	k8sv1 "k8s.io/api/core/v1"
+)
+
+// TopCmd is a struct that defines a command call for "top"
+type TopCmd struct {
+	flags *TopCmdFlags
+}
+
+// TopCmdFlags are the flags available for the top-command
+type TopCmdFlags struct {
+	service         string
+	namespace       string
+	labelSelector   string
+	container       string
+	switchContext   bool
+	config          string
+	configOverwrite string
+}
+
+func init() {
+	cmd := &TopCmd{
+		flags: &TopCmdFlags{},
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "top",
+		Short: "Shows the resource usage of the devspace containers",
+		Long: `
+#######################################################
+#################### devspace top #####################
+#######################################################
+Shows the cpu and memory usage of the devspace
+containers compared to their requests and limits, the
+number of restarts and why the containers were
+terminated last (e.g. OOMKilled).
+
+The usage is retrieved from the metrics api and
+requires metrics-server in the cluster.
+#######################################################`,
+		Args: cobra.NoArgs,
+		Run:  cmd.Run,
+	}
+	rootCmd.AddCommand(cobraCmd)
+
+	cobraCmd.Flags().StringVarP(&cmd.flags.service, "service", "s", "", "Service name (in config) to select pods (default: all services)")
+	cobraCmd.Flags().StringVarP(&cmd.flags.container, "container", "c", "", "Only show this container")
+	cobraCmd.Flags().StringVarP(&cmd.flags.labelSelector, "label-selector", "l", "", "Comma separated key=value selector list (e.g. release=test)")
+	cobraCmd.Flags().StringVarP(&cmd.flags.namespace, "namespace", "n", "", "Namespace where to select pods")
+	cobraCmd.Flags().BoolVar(&cmd.flags.switchContext, "switch-context", false, "Switch kubectl context to the devspace context")
+	cobraCmd.Flags().StringVar(&cmd.flags.config, "config", configutil.ConfigPath, "The devspace config file to load (default: '.devspace/config.yaml'")
+	cobraCmd.Flags().StringVar(&cmd.flags.configOverwrite, "config-overwrite", configutil.OverwriteConfigPath, "The devspace config overwrite file to load (default: '.devspace/overwrite.yaml'")
+}
+
+// Run executes the command logic
+func (cmd *TopCmd) Run(cobraCmd *cobra.Command, args []string) {
+	if configutil.ConfigPath != cmd.flags.config {
+		configutil.ConfigPath = cmd.flags.config
+
+		// Don't use overwrite config if we use a different config
+		configutil.OverwriteConfigPath = ""
+	}
+	if configutil.OverwriteConfigPath != cmd.flags.configOverwrite {
+		configutil.OverwriteConfigPath = cmd.flags.configOverwrite
+	}
+
+	log.StartFileLogging()
+
+	kubectl, err := kubectl.NewClientWithContextSwitch(cmd.flags.switchContext)
+	if err != nil {
+		log.Fatalf("Unable to create new kubectl client: %v", err)
+	}
+
+	log.StartWait("Retrieving resource usage")
+	usages, metricsAvailable, err := services.GetResourceUsage(kubectl, cmd.flags.service, cmd.flags.labelSelector, cmd.flags.namespace)
+	log.StopWait()
+	if err != nil {
+		log.Fatalf("Error retrieving resource usage: %v", err)
+	}
+
+	if metricsAvailable == false {
+		log.Warn("The metrics api is not available, therefore only requests, limits and restarts are shown. Install metrics-server in your cluster to see the cpu and memory usage")
+	}
+
+	values := [][]string{}
+	for _, usage := range usages {
+		if cmd.flags.container != "" && usage.Container != cmd.flags.container {
+			continue
+		}
+
+		values = append(values, getTopValues(usage))
+	}
+
+	if len(values) == 0 {
+		log.Info("No pods found")
+		return
+	}
+
+	log.PrintTable([]string{
+		"NAMESPACE",
+		"POD",
+		"CONTAINER",
+		"CPU",
+		"CPU REQ/LIMIT",
+		"MEMORY",
+		"MEMORY REQ/LIMIT",
+		"RESTARTS",
+		"LAST TERMINATION",
+	}, values)
+}
+
+func getTopValues(usage *kubectl.ContainerUsage) []string {
+	lastTermination := usage.LastTermination
+	if lastTermination == "" {
+		lastTermination = "-"
+	}
+
+	return []string{
+		usage.Namespace,
+		usage.Pod,
+		usage.Container,
+		kubectl.FormatUsage(usage.CPU, usage.Requests, usage.Limits, k8sv1.ResourceCPU),
+		kubectl.FormatResource(usage.Requests, k8sv1.ResourceCPU) + "/" + kubectl.FormatResource(usage.Limits, k8sv1.ResourceCPU),
+		kubectl.FormatUsage(usage.Memory, usage.Requests, usage.Limits, k8sv1.ResourceMemory),
+		kubectl.FormatResource(usage.Requests, k8sv1.ResourceMemory) + "/" + kubectl.FormatResource(usage.Limits, k8sv1.ResourceMemory),
+		strconv.Itoa(int(usage.Restarts)),
+		lastTermination,
+	}
+}
@@ -2,7 +2,10 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"os/signal"
 	"sync"
+	"syscall"
 	"time"
 
 	"github.com/covexo/devspace/pkg/devspace/watch"
@@ -40,11 +43,14 @@ type UpCmdFlags struct {
 	allyes          bool
 	switchContext   bool
 	portforwarding  bool
+	proxy           bool
 	verboseSync     bool
 	service         string
 	container       string
 	labelSelector   string
 	namespace       string
+	pick            bool
+	record          string
 	config          string
 	configOverwrite string
 }
@@ -63,6 +69,7 @@ var UpFlagsDefault = &UpCmdFlags{
 	allyes:          false,
 	deploy:          false,
 	portforwarding:  true,
+	proxy:           true,
 	verboseSync:     false,
 	container:       "",
 	namespace:       "",
@@ -104,12 +111,15 @@ Starts and connects your DevSpace:
 	cobraCmd.Flags().BoolVar(&cmd.flags.verboseSync, "verbose-sync", cmd.flags.verboseSync, "When enabled the sync will log every file change")
 
 	cobraCmd.Flags().BoolVar(&cmd.flags.portforwarding, "portforwarding", cmd.flags.portforwarding, "Enable port forwarding")
+	cobraCmd.Flags().BoolVar(&cmd.flags.proxy, "proxy", cmd.flags.proxy, "Enable the local hostname proxy for forwarded ports (if configured)")
 
 	cobraCmd.Flags().BoolVar(&cmd.flags.terminal, "terminal", cmd.flags.terminal, "Enable terminal")
 	cobraCmd.Flags().StringVarP(&cmd.flags.service, "service", "s", "", "Service name (in config) to select pods/container for terminal")
 	cobraCmd.Flags().StringVarP(&cmd.flags.container, "container", "c", cmd.flags.container, "Container name where to open the shell")
 	cobraCmd.Flags().StringVarP(&cmd.flags.labelSelector, "label-selector", "l", "", "Comma separated key=value selector list to use for terminal (e.g. release=test)")
 	cobraCmd.Flags().StringVarP(&cmd.flags.namespace, "namespace", "n", "", "Namespace where to select pods for terminal")
+	cobraCmd.Flags().BoolVar(&cmd.flags.pick, "pick", false, "Select the pod and container for the terminal interactively")
+	cobraCmd.Flags().StringVar(&cmd.flags.record, "record", "", "Record the terminal session to this file (asciicast v2 format)")
 
 	cobraCmd.Flags().BoolVar(&cmd.flags.switchContext, "switch-context", cmd.flags.switchContext, "Switch kubectl context to the devspace context")
 	cobraCmd.Flags().BoolVar(&cmd.flags.exitAfterDeploy, "exit-after-deploy", cmd.flags.exitAfterDeploy, "Exits the command after building the images and deploying the devspace")
@@ -186,8 +196,19 @@ func (cmd *UpCmd) Run(cobraCmd *cobra.Command, args []string) {
 		}
 	}
 
+	// Restore the original workloads of services in dev mode if up is interrupted
+	if services.HasDevMode() {
+		stopDevModeOnInterrupt(client)
+	}
+
 	// Build and deploy images
 	err = buildAndDeploy(client, cmd.flags, args)
+
+	// Restore the original workloads of services in dev mode, which stay in dev mode during reloads
+	if cmd.flags.exitAfterDeploy == false && services.HasDevMode() {
+		services.StopDevMode(client, log.GetInstance())
+	}
+
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -233,6 +254,19 @@ func buildAndDeploy(client *kubernetes.Clientset, flags *UpCmdFlags, args []stri
 		}
 	}
 
+	// Replace the workloads of services in dev mode
+	if services.HasDevMode() {
+		generatedConfig, err := generated.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("Error loading generated.yaml: %v", err)
+		}
+
+		err = services.StartDevMode(client, generatedConfig, log.GetInstance())
+		if err != nil {
+			return err
+		}
+	}
+
 	// Start services
 	if flags.exitAfterDeploy == false {
 		// Start services
@@ -263,6 +297,23 @@ func startServices(client *kubernetes.Clientset, flags *UpCmdFlags, args []strin
 				v.Close()
 			}
 		}()
+
+		portProbes := services.StartPortProbes(log)
+		defer func() {
+			for _, probe := range portProbes {
+				probe.Stop()
+			}
+		}()
+
+		if flags.proxy {
+			// The proxy is a convenience, so devspace up continues without it
+			proxy, err := services.StartProxy(log)
+			if err != nil {
+				log.Warnf("Unable to start proxy: %v", err)
+			} else if proxy != nil {
+				defer proxy.Close()
+			}
+		}
 	}
 
 	if flags.sync {
@@ -286,9 +337,28 @@ func startServices(client *kubernetes.Clientset, flags *UpCmdFlags, args []strin
 	}
 
 	config := configutil.GetConfig()
-	exitChan := make(chan error)
+	exitChan := make(chan error, 1)
 	autoReloadPaths := GetPaths()
 
+	// An interrupt ends the services like a normal exit, the terminal and attach session return on the nil error
+	interruptChan := setServicesRunning(true)
+	servicesDone := make(chan struct{})
+	defer func() {
+		setServicesRunning(false)
+		close(servicesDone)
+	}()
+
+	go func() {
+		select {
+		case <-interruptChan:
+			select {
+			case exitChan <- nil:
+			default:
+			}
+		case <-servicesDone:
+		}
+	}()
+
 	// Start watcher if we have at least one auto reload path and if we should not skip the pipeline
 	if flags.skipPipeline == false && len(autoReloadPaths) > 0 {
 		var once sync.Once
@@ -311,7 +381,7 @@ func startServices(client *kubernetes.Clientset, flags *UpCmdFlags, args []strin
 	}
 
 	if flags.terminal && (config.DevSpace == nil || config.DevSpace.Terminal == nil || config.DevSpace.Terminal.Disabled == nil || *config.DevSpace.Terminal.Disabled == false) {
-		return services.StartTerminal(client, flags.service, flags.container, flags.labelSelector, flags.namespace, args, exitChan, log)
+		return services.StartTerminal(client, flags.service, flags.container, flags.labelSelector, flags.namespace, args, nil, flags.pick, flags.record, exitChan, log)
 	}
 
 	log.Info("Will now try to print the logs of a running devspace pod...")
@@ -328,7 +398,13 @@ func startServices(client *kubernetes.Clientset, flags *UpCmdFlags, args []strin
 	}
 
 	log.Done("Services started (Press Ctrl+C to abort port-forwarding and sync)")
-	return <-exitChan
+
+	select {
+	case err := <-exitChan:
+		return err
+	case <-interruptChan:
+		return nil
+	}
 }
 
 // GetPaths retrieves the watch paths from the config object
@@ -384,6 +460,50 @@ func GetPaths() []string {
 	return paths
 }
 
+// interrupted is closed when devspace up receives SIGINT or SIGTERM while services are in dev mode
+var interrupted = make(chan struct{})
+
+// servicesRunning is true while startServices waits for the services, it is guarded by interruptMutex
+var (
+	interruptMutex  sync.Mutex
+	servicesRunning bool
+)
+
+// stopDevModeOnInterrupt restores the original workloads of services in dev mode if the process is interrupted. While the
+// services run, the interrupt ends them like a normal exit, so that sync, port forwarding and proxy are stopped by their
+// deferred cleanup before Run restores the workloads. Otherwise (e.g. during the build) or on a second interrupt, the
+// workloads are restored directly
+func stopDevModeOnInterrupt(client *kubernetes.Clientset) {
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		<-signals
+
+		interruptMutex.Lock()
+		close(interrupted)
+		running := servicesRunning
+		interruptMutex.Unlock()
+
+		if running {
+			<-signals
+		}
+
+		log.StopWait()
+		services.StopDevMode(client, log.GetInstance())
+		os.Exit(0)
+	}()
+}
+
+// setServicesRunning marks the services as running and returns a channel that is closed if devspace up is interrupted
+func setServicesRunning(running bool) <-chan struct{} {
+	interruptMutex.Lock()
+	defer interruptMutex.Unlock()
+
+	servicesRunning = running
+	return interrupted
+}
+
 type reloadError struct {
 }
 
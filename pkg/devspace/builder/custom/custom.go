@@ -0,0 +1,183 @@
+package custom
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/covexo/devspace/pkg/devspace/builder"
+	"github.com/covexo/devspace/pkg/devspace/builder/docker"
+	dockerclient "github.com/covexo/devspace/pkg/devspace/docker"
+	"github.com/covexo/devspace/pkg/util/log"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+)
+
+// Environment variables that are passed to the command and can be used as placeholders in the command and its args
+const (
+	EnvImage      = "DEVSPACE_IMAGE"
+	EnvImageName  = "DEVSPACE_IMAGE_NAME"
+	EnvImageTag   = "DEVSPACE_IMAGE_TAG"
+	EnvContext    = "DEVSPACE_CONTEXT"
+	EnvDockerfile = "DEVSPACE_DOCKERFILE"
+)
+
+// EnvSecretPrefix is the prefix of the environment variables that contain the paths of the build secret files,
+// e.g. DEVSPACE_SECRET_NPMRC for the secret with the id npmrc
+const EnvSecretPrefix = "DEVSPACE_SECRET_"
+
+// Builder builds images with a user defined command, e.g. buildah, img, jib or bazel
+type Builder struct {
+	Command string
+	Args    []string
+
+	registryURL   string
+	name          string
+	imageName     string
+	imageTag      string
+	dockerClient  client.CommonAPIClient
+	dockerBuilder *docker.Builder
+	log           log.Logger
+}
+
+// NewBuilder creates a new custom.Builder instance. The image the command built is pushed with the local docker daemon,
+// commands that push the image themselves have to be combined with images[].skipPush
+func NewBuilder(registryURL, imageName, imageTag, command string, args []string, log log.Logger) (*Builder, error) {
+	if command == "" {
+		return nil, fmt.Errorf("No command defined for image %s", imageName)
+	}
+
+	fullImageName := imageName
+	if registryURL != "" {
+		fullImageName = registryURL + "/" + imageName
+	}
+
+	return &Builder{
+		Command:     command,
+		Args:        args,
+		registryURL: registryURL,
+		name:        imageName,
+		imageName:   fullImageName,
+		imageTag:    imageTag,
+		log:         log,
+	}, nil
+}
+
+// getDockerBuilder creates the docker builder that pushes the image on first use, so that commands with skipPush don't need docker
+func (b *Builder) getDockerBuilder() (*docker.Builder, error) {
+	if b.dockerBuilder != nil {
+		return b.dockerBuilder, nil
+	}
+
+	dockerClient, err := dockerclient.NewClient(false)
+	if err != nil {
+		return nil, fmt.Errorf("Error creating docker client for pushing: %v", err)
+	}
+
+	dockerBuilder, err := docker.NewBuilder(dockerClient, b.registryURL, b.name, b.imageTag, b.log)
+	if err != nil {
+		return nil, err
+	}
+
+	b.dockerClient = dockerClient
+	b.dockerBuilder = dockerBuilder
+
+	return dockerBuilder, nil
+}
+
+// Authenticate authenticates the docker daemon that pushes the image. Commands that push the image themselves have to authenticate on their own
+func (b *Builder) Authenticate(username, password string, checkCredentialsStore bool) (*types.AuthConfig, error) {
+	dockerBuilder, err := b.getDockerBuilder()
+	if err != nil {
+		return nil, err
+	}
+
+	return dockerBuilder.Authenticate(username, password, checkCredentialsStore)
+}
+
+// BuildImage runs the command in the current working directory, the build succeeds if the command exits with exit code 0
+// The ssh agent is available to the command through the inherited SSH_AUTH_SOCK
+func (b *Builder) BuildImage(contextPath, dockerfilePath string, options *types.ImageBuildOptions, secrets *builder.BuildSecrets) error {
+	values := map[string]string{
+		EnvImage:      b.imageName + ":" + b.imageTag,
+		EnvImageName:  b.imageName,
+		EnvImageTag:   b.imageTag,
+		EnvContext:    contextPath,
+		EnvDockerfile: dockerfilePath,
+	}
+
+	if secrets != nil {
+		for id, file := range secrets.Files {
+			values[getSecretEnvName(id)] = file
+		}
+	}
+
+	expand := func(value string) string {
+		return os.Expand(value, func(name string) string {
+			if value, ok := values[name]; ok {
+				return value
+			}
+
+			return os.Getenv(name)
+		})
+	}
+
+	args := make([]string, 0, len(b.Args))
+	for _, arg := range b.Args {
+		args = append(args, expand(arg))
+	}
+
+	cmd := exec.Command(expand(b.Command), args...)
+	cmd.Env = os.Environ()
+	for name, value := range values {
+		cmd.Env = append(cmd.Env, name+"="+value)
+	}
+
+	cmd.Stdout = b.log
+	cmd.Stderr = b.log
+
+	b.log.Infof("Running %s", strings.Join(cmd.Args, " "))
+
+	err := cmd.Run()
+	if err != nil {
+		return fmt.Errorf("Build command %s failed: %v", b.Command, err)
+	}
+
+	return nil
+}
+
+func getSecretEnvName(id string) string {
+	name := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+
+		return '_'
+	}, id)
+
+	return EnvSecretPrefix + strings.ToUpper(name)
+}
+
+// PushImage pushes the image with the local docker daemon. Commands that don't build into the docker daemon (e.g. buildah or bazel)
+// have to push the image themselves and set images[].skipPush
+func (b *Builder) PushImage() error {
+	dockerBuilder, err := b.getDockerBuilder()
+	if err != nil {
+		return err
+	}
+
+	image := b.imageName + ":" + b.imageTag
+
+	_, _, err = b.dockerClient.ImageInspectWithRaw(context.Background(), image)
+	if err != nil {
+		if client.IsErrNotFound(err) {
+			return fmt.Errorf("Image %s was not found in the local docker daemon. If the build command does not build into the docker daemon, push the image in the command and set skipPush: true for the image", image)
+		}
+
+		return fmt.Errorf("Error inspecting image %s: %v", image, err)
+	}
+
+	return dockerBuilder.PushImage()
+}
@@ -4,11 +4,15 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 
+	"github.com/covexo/devspace/pkg/devspace/builder"
 	dockerclient "github.com/covexo/devspace/pkg/devspace/docker"
+	"github.com/covexo/devspace/pkg/util/log"
 
 	"github.com/docker/distribution/reference"
 	"github.com/docker/docker/pkg/term"
@@ -38,13 +42,17 @@ type Builder struct {
 	ImageName   string
 	ImageTag    string
 
+	// Environment contains the environment variables the docker cli needs to use the docker daemon of the client
+	Environment map[string]string
+
 	imageURL   string
 	authConfig *types.AuthConfig
 	client     client.CommonAPIClient
+	log        log.Logger
 }
 
 // NewBuilder creates a new docker Builder instance
-func NewBuilder(client client.CommonAPIClient, registryURL, imageName, imageTag string) (*Builder, error) {
+func NewBuilder(client client.CommonAPIClient, registryURL, imageName, imageTag string, log log.Logger) (*Builder, error) {
 	imageURL := imageName + ":" + imageTag
 	if registryURL != "" {
 		// Check if it's the official registry or not
@@ -69,19 +77,32 @@ func NewBuilder(client client.CommonAPIClient, registryURL, imageName, imageTag
 		ImageTag:    imageTag,
 		imageURL:    imageURL,
 		client:      client,
+		log:         log,
 	}, nil
 }
 
+// getOutStream returns the stream the docker output is written to. The output is always written to the logger and
+// not directly to the terminal, so that the lines are prefixed during parallel builds and end up in the build log
+// without terminal control sequences
+func (b *Builder) getOutStream() *command.OutStream {
+	return command.NewOutStream(b.log)
+}
+
 // BuildImage builds a dockerimage with the docker cli
 // contextPath is the absolute path to the context path
 // dockerfilePath is the absolute path to the dockerfile WITHIN the contextPath
-func (b *Builder) BuildImage(contextPath, dockerfilePath string, options *types.ImageBuildOptions) error {
+func (b *Builder) BuildImage(contextPath, dockerfilePath string, options *types.ImageBuildOptions, secrets *builder.BuildSecrets) error {
 	if options == nil {
 		options = &types.ImageBuildOptions{}
 	}
 
+	// Secrets require a BuildKit session, which is provided by the docker cli
+	if secrets.IsEmpty() == false {
+		return b.buildImageWithBuildKit(contextPath, dockerfilePath, options, secrets)
+	}
+
 	ctx := context.Background()
-	outStream := command.NewOutStream(stdout)
+	outStream := b.getOutStream()
 	contextDir, relDockerfile, err := build.GetContextFromLocalDir(contextPath, dockerfilePath)
 	if err != nil {
 		return err
@@ -154,6 +175,54 @@ func (b *Builder) BuildImage(contextPath, dockerfilePath string, options *types.
 	return nil
 }
 
+// buildImageWithBuildKit builds the image with the docker cli and BuildKit, which mounts the secrets with
+// RUN --mount=type=secret,id=<id> and forwards the ssh agent with RUN --mount=type=ssh (requires docker 18.09)
+func (b *Builder) buildImageWithBuildKit(contextPath, dockerfilePath string, options *types.ImageBuildOptions, secrets *builder.BuildSecrets) error {
+	args := []string{"build", "--progress=plain", "--file", dockerfilePath, "--tag", b.imageURL}
+
+	for key, value := range options.BuildArgs {
+		if value != nil {
+			args = append(args, "--build-arg", key+"="+*value)
+		}
+	}
+	if options.Target != "" {
+		args = append(args, "--target", options.Target)
+	}
+	if options.NetworkMode != "" {
+		args = append(args, "--network", options.NetworkMode)
+	}
+
+	for id, file := range secrets.Files {
+		args = append(args, "--secret", "id="+id+",src="+file)
+	}
+
+	if secrets.SSH {
+		if os.Getenv("SSH_AUTH_SOCK") == "" {
+			return errors.New("Cannot forward the ssh agent: SSH_AUTH_SOCK is not set")
+		}
+
+		args = append(args, "--ssh", "default")
+	}
+
+	args = append(args, contextPath)
+
+	cmd := exec.Command("docker", args...)
+	cmd.Env = append(os.Environ(), "DOCKER_BUILDKIT=1")
+	for name, value := range b.Environment {
+		cmd.Env = append(cmd.Env, name+"="+value)
+	}
+
+	cmd.Stdout = b.log
+	cmd.Stderr = b.log
+
+	err := cmd.Run()
+	if err != nil {
+		return fmt.Errorf("Error building image: %v", err)
+	}
+
+	return nil
+}
+
 // Authenticate authenticates the client with a remote registry
 func (b *Builder) Authenticate(user, password string, checkCredentialsStore bool) (*types.AuthConfig, error) {
 	var err error
@@ -186,7 +255,7 @@ func (b *Builder) PushImage() error {
 		return err
 	}
 
-	outStream := command.NewOutStream(stdout)
+	outStream := b.getOutStream()
 	err = jsonmessage.DisplayJSONMessagesStream(out, outStream, outStream.FD(), outStream.IsTerminal(), nil)
 	if err != nil {
 		return err
@@ -5,6 +5,20 @@ import "github.com/docker/docker/api/types"
 // Interface defines methods for builders (e.g. docker, kaniko)
 type Interface interface {
 	Authenticate(username, password string, checkCredentialsStore bool) (*types.AuthConfig, error)
-	BuildImage(contextPath, dockerfilePath string, options *types.ImageBuildOptions) error
+	BuildImage(contextPath, dockerfilePath string, options *types.ImageBuildOptions, secrets *BuildSecrets) error
 	PushImage() error
 }
+
+// BuildSecrets are made available to the build without being stored in the image
+type BuildSecrets struct {
+	// Files maps the secret ids to the local files that contain the secrets
+	Files map[string]string
+
+	// SSH forwards the local ssh agent (SSH_AUTH_SOCK)
+	SSH bool
+}
+
+// IsEmpty returns true if no secrets are defined
+func (b *BuildSecrets) IsEmpty() bool {
+	return b == nil || (len(b.Files) == 0 && b.SSH == false)
+}
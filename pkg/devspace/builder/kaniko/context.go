@@ -0,0 +1,207 @@
+package kaniko
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/covexo/devspace/pkg/devspace/kubectl"
+	"github.com/covexo/devspace/pkg/util/log"
+	"github.com/docker/cli/cli/command/image/build"
+	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/docker/pkg/idtools"
+	units "github.com/docker/go-units"
+	k8sv1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// DefaultMaxContextSize is the maximum size of the uncompressed build context
+const DefaultMaxContextSize = 1024 * 1024 * 1024
+
+// extractScript extracts the uploaded context archive in the root directory of the build pod
+const extractScript = "mkdir -p " + containerBuildPath + " " + containerDockerfilePath + " && tar xzf - -C /"
+
+// contextArchive is a gzip compressed tar of the build context and the Dockerfile
+type contextArchive struct {
+	Path string
+	Size int64
+
+	// ContextSize is the uncompressed size of the context files
+	ContextSize int64
+}
+
+// createContextArchive writes the context filtered by the .dockerignore (like docker build) and the Dockerfile to
+// a temporary archive. The context is placed below /src and the Dockerfile below /kaniko/dockerfile, so that the
+// archive can be extracted in one step. Fails if the uncompressed context exceeds maxSize (0 means no limit)
+func createContextArchive(contextPath, dockerfilePath string, maxSize int64) (*contextArchive, error) {
+	contextDir, _, err := build.GetContextFromLocalDir(contextPath, dockerfilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	excludes, err := build.ReadDockerignore(contextDir)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading .dockerignore: %v", err)
+	}
+
+	if err := build.ValidateContextDirectory(contextDir, excludes); err != nil {
+		return nil, fmt.Errorf("Error checking context: %v", err)
+	}
+
+	dockerfileData, err := ioutil.ReadFile(dockerfilePath)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to read Dockerfile: %v", err)
+	}
+
+	contextTar, err := archive.TarWithOptions(contextDir, &archive.TarOptions{
+		ExcludePatterns: excludes,
+		ChownOpts:       &idtools.Identity{UID: 0, GID: 0},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer contextTar.Close()
+
+	archiveFile, err := ioutil.TempFile("", "devspace-context-*.tar.gz")
+	if err != nil {
+		return nil, err
+	}
+
+	result := &contextArchive{
+		Path: archiveFile.Name(),
+	}
+
+	err = writeContextArchive(archiveFile, contextTar, filepath.Base(dockerfilePath), dockerfileData, maxSize, result)
+	archiveFile.Close()
+	if err != nil {
+		os.Remove(result.Path)
+		return nil, err
+	}
+
+	stat, err := os.Stat(result.Path)
+	if err != nil {
+		os.Remove(result.Path)
+		return nil, err
+	}
+
+	result.Size = stat.Size()
+	return result, nil
+}
+
+func writeContextArchive(writer io.Writer, contextTar io.Reader, dockerfileName string, dockerfileData []byte, maxSize int64, result *contextArchive) error {
+	gzipWriter := gzip.NewWriter(writer)
+	tarWriter := tar.NewWriter(gzipWriter)
+	tarReader := tar.NewReader(contextTar)
+	contextPrefix := strings.TrimPrefix(containerBuildPath, "/")
+
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("Error packing context: %v", err)
+		}
+
+		result.ContextSize += header.Size
+		if maxSize > 0 && result.ContextSize > maxSize {
+			return fmt.Errorf("Build context exceeds the maximum size of %s, please exclude files with a .dockerignore or increase kaniko.maxContextSize", units.HumanSize(float64(maxSize)))
+		}
+
+		header.Name = path.Join(contextPrefix, header.Name)
+		if header.Typeflag == tar.TypeLink {
+			header.Linkname = path.Join(contextPrefix, header.Linkname)
+		}
+
+		err = tarWriter.WriteHeader(header)
+		if err != nil {
+			return err
+		}
+
+		_, err = io.Copy(tarWriter, tarReader)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := tarWriter.WriteHeader(&tar.Header{
+		Name:     path.Join(strings.TrimPrefix(containerDockerfilePath, "/"), dockerfileName),
+		Mode:     0644,
+		Size:     int64(len(dockerfileData)),
+		ModTime:  time.Now(),
+		Typeflag: tar.TypeReg,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = tarWriter.Write(dockerfileData)
+	if err != nil {
+		return err
+	}
+
+	err = tarWriter.Close()
+	if err != nil {
+		return err
+	}
+
+	return gzipWriter.Close()
+}
+
+// uploadContextArchive streams the archive into the build container and extracts it there
+func uploadContextArchive(client *kubernetes.Clientset, pod *k8sv1.Pod, container string, uploadArchive *contextArchive, log log.Logger) error {
+	file, err := os.Open(uploadArchive.Path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader := &uploadProgressReader{
+		reader: file,
+		total:  uploadArchive.Size,
+		log:    log,
+	}
+
+	stderr := &bytes.Buffer{}
+	err = kubectl.ExecStream(client, pod, container, []string{"sh", "-c", extractScript}, false, reader, &bytes.Buffer{}, stderr)
+	if err != nil {
+		return fmt.Errorf("%v %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
+
+// uploadProgressReader shows the upload progress in steps of 10 percent, so that loggers that print every wait
+// message (e.g. during parallel builds) don't print a line for every chunk
+type uploadProgressReader struct {
+	reader io.Reader
+	total  int64
+	read   int64
+	log    log.Logger
+
+	lastPercent int64
+}
+
+// Read implements the io.Reader interface
+func (u *uploadProgressReader) Read(buf []byte) (int, error) {
+	n, err := u.reader.Read(buf)
+	u.read += int64(n)
+
+	if n > 0 && u.total > 0 {
+		percent := u.read * 100 / u.total / 10 * 10
+		if percent != u.lastPercent {
+			u.lastPercent = percent
+			u.log.StartWait(fmt.Sprintf("Uploading build context (%d%% of %s)", percent, units.HumanSize(float64(u.total))))
+		}
+	}
+
+	return n, err
+}
@@ -0,0 +1,214 @@
+package kaniko
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type testTarEntry struct {
+	header *tar.Header
+	data   string
+}
+
+func TestWriteContextArchive(t *testing.T) {
+	contextTar := createTestTar(t, []testTarEntry{
+		{header: &tar.Header{Name: "app/", Typeflag: tar.TypeDir, Mode: 0755}},
+		{header: &tar.Header{Name: "app/main.go", Typeflag: tar.TypeReg, Mode: 0644}, data: "package main"},
+		{header: &tar.Header{Name: "app/link.go", Typeflag: tar.TypeLink, Linkname: "app/main.go"}},
+		{header: &tar.Header{Name: "app/symlink.go", Typeflag: tar.TypeSymlink, Linkname: "main.go"}},
+	})
+
+	result := &contextArchive{}
+	output := &bytes.Buffer{}
+
+	err := writeContextArchive(output, contextTar, "Dockerfile.dev", []byte("FROM alpine"), 0, result)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.ContextSize != int64(len("package main")) {
+		t.Fatalf("Expected context size %d, got %d", len("package main"), result.ContextSize)
+	}
+
+	entries := readTestArchive(t, output)
+
+	expectedEntries := []struct {
+		name     string
+		typeflag byte
+		linkname string
+		data     string
+	}{
+		{name: "src/app", typeflag: tar.TypeDir},
+		{name: "src/app/main.go", typeflag: tar.TypeReg, data: "package main"},
+		{name: "src/app/link.go", typeflag: tar.TypeLink, linkname: "src/app/main.go"},
+		{name: "src/app/symlink.go", typeflag: tar.TypeSymlink, linkname: "main.go"},
+		{name: "kaniko/dockerfile/Dockerfile.dev", typeflag: tar.TypeReg, data: "FROM alpine"},
+	}
+
+	if len(entries) != len(expectedEntries) {
+		t.Fatalf("Expected %d entries in the archive, got %d", len(expectedEntries), len(entries))
+	}
+
+	for i, expected := range expectedEntries {
+		entry := entries[i]
+
+		if entry.header.Name != expected.name {
+			t.Fatalf("Expected entry %d to be %s, got %s", i, expected.name, entry.header.Name)
+		}
+		if entry.header.Typeflag != expected.typeflag {
+			t.Fatalf("Expected type %c for %s, got %c", expected.typeflag, expected.name, entry.header.Typeflag)
+		}
+		if entry.header.Linkname != expected.linkname {
+			t.Fatalf("Expected link name %s for %s, got %s", expected.linkname, expected.name, entry.header.Linkname)
+		}
+		if entry.data != expected.data {
+			t.Fatalf("Expected content %s for %s, got %s", expected.data, expected.name, entry.data)
+		}
+	}
+}
+
+func TestWriteContextArchiveMaxSize(t *testing.T) {
+	testCases := []struct {
+		name        string
+		maxSize     int64
+		expectedErr bool
+	}{
+		{
+			name:    "no limit",
+			maxSize: 0,
+		},
+		{
+			name:    "context size equals limit",
+			maxSize: 10,
+		},
+		{
+			name:        "context exceeds limit",
+			maxSize:     9,
+			expectedErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		contextTar := createTestTar(t, []testTarEntry{
+			{header: &tar.Header{Name: "first", Typeflag: tar.TypeReg, Mode: 0644}, data: "12345"},
+			{header: &tar.Header{Name: "second", Typeflag: tar.TypeReg, Mode: 0644}, data: "67890"},
+		})
+
+		err := writeContextArchive(ioutil.Discard, contextTar, "Dockerfile", []byte("FROM alpine"), testCase.maxSize, &contextArchive{})
+		if testCase.expectedErr && err == nil {
+			t.Fatalf("Test case %s: expected an error", testCase.name)
+		}
+		if testCase.expectedErr == false && err != nil {
+			t.Fatalf("Test case %s: %v", testCase.name, err)
+		}
+	}
+}
+
+func TestCreateContextArchive(t *testing.T) {
+	contextPath, err := ioutil.TempDir("", "devspace-test-context-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(contextPath)
+
+	dockerfileDir, err := ioutil.TempDir("", "devspace-test-dockerfile-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dockerfileDir)
+
+	// The Dockerfile is outside of the context and has another name
+	dockerfilePath := filepath.Join(dockerfileDir, "build.Dockerfile")
+	ioutil.WriteFile(dockerfilePath, []byte("FROM alpine"), 0666)
+	ioutil.WriteFile(filepath.Join(contextPath, ".dockerignore"), []byte("*.log"), 0666)
+	ioutil.WriteFile(filepath.Join(contextPath, "main.go"), []byte("package main"), 0666)
+	ioutil.WriteFile(filepath.Join(contextPath, "debug.log"), []byte("log"), 0666)
+
+	result, err := createContextArchive(contextPath, dockerfilePath, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(result.Path)
+
+	archiveFile, err := os.Open(result.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer archiveFile.Close()
+
+	names := map[string]bool{}
+	for _, entry := range readTestArchive(t, archiveFile) {
+		names[entry.header.Name] = true
+	}
+
+	if names["src/main.go"] == false {
+		t.Fatalf("Expected src/main.go in the archive, got %v", names)
+	}
+	if names["src/debug.log"] {
+		t.Fatalf("Expected src/debug.log to be excluded by the .dockerignore")
+	}
+	if names["kaniko/dockerfile/build.Dockerfile"] == false {
+		t.Fatalf("Expected kaniko/dockerfile/build.Dockerfile in the archive, got %v", names)
+	}
+}
+
+func createTestTar(t *testing.T, entries []testTarEntry) io.Reader {
+	buffer := &bytes.Buffer{}
+	tarWriter := tar.NewWriter(buffer)
+
+	for _, entry := range entries {
+		entry.header.Size = int64(len(entry.data))
+
+		err := tarWriter.WriteHeader(entry.header)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = tarWriter.Write([]byte(entry.data))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err := tarWriter.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return buffer
+}
+
+func readTestArchive(t *testing.T, reader io.Reader) []testTarEntry {
+	gzipReader, err := gzip.NewReader(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries := []testTarEntry{}
+	tarReader := tar.NewReader(gzipReader)
+
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		data, err := ioutil.ReadAll(tarReader)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		entries = append(entries, testTarEntry{header: header, data: string(data)})
+	}
+
+	return entries
+}
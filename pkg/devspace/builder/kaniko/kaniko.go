@@ -1,54 +1,305 @@
 package kaniko
 
 import (
+	"errors"
 	"fmt"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/covexo/devspace/pkg/devspace/builder"
+	"github.com/covexo/devspace/pkg/devspace/config/v1"
 	"github.com/covexo/devspace/pkg/devspace/docker"
 	"github.com/covexo/devspace/pkg/devspace/registry"
 
 	"github.com/covexo/devspace/pkg/devspace/kubectl"
-	synctool "github.com/covexo/devspace/pkg/devspace/sync"
-	"github.com/covexo/devspace/pkg/util/ignoreutil"
 	"github.com/covexo/devspace/pkg/util/log"
 	"github.com/covexo/devspace/pkg/util/randutil"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/client"
+	units "github.com/docker/go-units"
 	k8sv1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/kubernetes/pkg/util/interrupt"
 )
 
+// DefaultExecutorImage is the kaniko executor image of the build pod. Other images have to be debug images as well,
+// because the build pod needs a shell
+const DefaultExecutorImage = "gcr.io/kaniko-project/executor:debug-5ac29a97734170a0547fea33b348dc7c328e2f8a"
+
+// DefaultStartTimeout is the time the build pod may take to start
+const DefaultStartTimeout = 2 * time.Minute
+
+// containerBuildPath is the directory the context is uploaded to
+const containerBuildPath = "/src"
+
+// containerDockerfilePath is the directory the Dockerfile is uploaded to. It is separate from the context, so that
+// Dockerfiles outside of the context and with other names than Dockerfile can be used
+const containerDockerfilePath = "/kaniko/dockerfile"
+
+// containerCacheDir is the directory the base image cache volume is mounted to
+const containerCacheDir = "/cache"
+
+// cacheVolumeName is the name of the base image cache volume in the build and warmer pods
+const cacheVolumeName = "kaniko-cache"
+
+// containerSecretsPath is the directory the build secrets are mounted to
+const containerSecretsPath = "/run/secrets"
+
 // Builder holds the necessary information to build and push docker images
 type Builder struct {
 	RegistryURL      string
 	PullSecretName   string
 	ImageName        string
 	ImageTag         string
-	PreviousImageTag string
 	BuildNamespace   string
+	Cache            bool
+	CacheRepo        string
+	CacheVolumeClaim string
+	ExecutorImage    string
+	ServiceAccount   string
+	Resources        k8sv1.ResourceRequirements
+	NodeSelector     map[string]string
+	Tolerations      []k8sv1.Toleration
+	Flags            []string
+	StartTimeout     time.Duration
+	BuildTimeout     time.Duration
+	MaxContextSize   int64
+	Verbose          bool
 
 	allowInsecureRegistry bool
 	kubectl               *kubernetes.Clientset
 	dockerClient          client.CommonAPIClient
+	log                   log.Logger
 }
 
 // NewBuilder creates a new kaniko.Builder instance
-func NewBuilder(registryURL, pullSecretName, imageName, imageTag, lastImageTag, buildNamespace string, dockerClient client.CommonAPIClient, kubectl *kubernetes.Clientset, allowInsecureRegistry bool) (*Builder, error) {
-	return &Builder{
+func NewBuilder(registryURL, imageName, imageTag, buildNamespace string, kanikoConfig *v1.KanikoConfig, dockerClient client.CommonAPIClient, kubectl *kubernetes.Clientset, allowInsecureRegistry bool, log log.Logger) (*Builder, error) {
+	builder := &Builder{
 		RegistryURL:           registryURL,
-		PullSecretName:        pullSecretName,
 		ImageName:             imageName,
 		ImageTag:              imageTag,
-		PreviousImageTag:      lastImageTag,
 		BuildNamespace:        buildNamespace,
+		Cache:                 true,
+		ExecutorImage:         DefaultExecutorImage,
+		NodeSelector:          map[string]string{},
+		Tolerations:           []k8sv1.Toleration{},
+		Flags:                 []string{},
+		StartTimeout:          DefaultStartTimeout,
+		MaxContextSize:        DefaultMaxContextSize,
 		allowInsecureRegistry: allowInsecureRegistry,
 		kubectl:               kubectl,
 		dockerClient:          dockerClient,
-	}, nil
+		log:                   log,
+	}
+
+	if kanikoConfig == nil {
+		return builder, nil
+	}
+
+	if kanikoConfig.Cache != nil {
+		builder.Cache = *kanikoConfig.Cache
+	}
+	if kanikoConfig.CacheRepo != nil {
+		builder.CacheRepo = *kanikoConfig.CacheRepo
+	}
+	if kanikoConfig.CacheVolumeClaim != nil {
+		builder.CacheVolumeClaim = *kanikoConfig.CacheVolumeClaim
+	}
+	if kanikoConfig.PullSecret != nil {
+		builder.PullSecretName = *kanikoConfig.PullSecret
+	}
+	if kanikoConfig.Image != nil && *kanikoConfig.Image != "" {
+		builder.ExecutorImage = *kanikoConfig.Image
+	}
+	if kanikoConfig.ServiceAccount != nil {
+		builder.ServiceAccount = *kanikoConfig.ServiceAccount
+	}
+
+	if kanikoConfig.Resources != nil {
+		requests, err := parseResourceList(kanikoConfig.Resources.Requests)
+		if err != nil {
+			return nil, fmt.Errorf("Error parsing kaniko resource requests: %v", err)
+		}
+
+		limits, err := parseResourceList(kanikoConfig.Resources.Limits)
+		if err != nil {
+			return nil, fmt.Errorf("Error parsing kaniko resource limits: %v", err)
+		}
+
+		builder.Resources = k8sv1.ResourceRequirements{
+			Requests: requests,
+			Limits:   limits,
+		}
+	}
+
+	if kanikoConfig.NodeSelector != nil {
+		for key, value := range *kanikoConfig.NodeSelector {
+			builder.NodeSelector[key] = *value
+		}
+	}
+
+	if kanikoConfig.Tolerations != nil {
+		for _, tolerationConfig := range *kanikoConfig.Tolerations {
+			toleration, err := getToleration(tolerationConfig)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid kaniko toleration: %v", err)
+			}
+
+			builder.Tolerations = append(builder.Tolerations, toleration)
+		}
+	}
+
+	if kanikoConfig.Flags != nil {
+		for _, flag := range *kanikoConfig.Flags {
+			builder.Flags = append(builder.Flags, *flag)
+		}
+	}
+
+	if kanikoConfig.StartTimeout != nil && *kanikoConfig.StartTimeout > 0 {
+		builder.StartTimeout = time.Duration(*kanikoConfig.StartTimeout) * time.Second
+	}
+	if kanikoConfig.BuildTimeout != nil && *kanikoConfig.BuildTimeout > 0 {
+		builder.BuildTimeout = time.Duration(*kanikoConfig.BuildTimeout) * time.Second
+	}
+
+	if kanikoConfig.MaxContextSize != nil {
+		maxContextSize, err := resource.ParseQuantity(*kanikoConfig.MaxContextSize)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid kaniko maxContextSize %s: %v", *kanikoConfig.MaxContextSize, err)
+		}
+
+		builder.MaxContextSize = maxContextSize.Value()
+	}
+
+	return builder, nil
+}
+
+func parseResourceList(resources *map[string]*string) (k8sv1.ResourceList, error) {
+	if resources == nil {
+		return nil, nil
+	}
+
+	resourceList := k8sv1.ResourceList{}
+	for name, value := range *resources {
+		if value == nil {
+			return nil, fmt.Errorf("Missing quantity for %s", name)
+		}
+
+		quantity, err := resource.ParseQuantity(*value)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid quantity %s for %s: %v", *value, name, err)
+		}
+
+		resourceList[k8sv1.ResourceName(name)] = quantity
+	}
+
+	return resourceList, nil
+}
+
+// getToleration converts the toleration config and validates it like the api server, so that an invalid toleration
+// fails before the build pod is created
+func getToleration(toleration *v1.TolerationConfig) (k8sv1.Toleration, error) {
+	result := k8sv1.Toleration{
+		TolerationSeconds: toleration.TolerationSeconds,
+	}
+
+	if toleration.Key != nil {
+		result.Key = *toleration.Key
+	}
+	if toleration.Operator != nil {
+		result.Operator = k8sv1.TolerationOperator(*toleration.Operator)
+	}
+	if toleration.Value != nil {
+		result.Value = *toleration.Value
+	}
+	if toleration.Effect != nil {
+		result.Effect = k8sv1.TaintEffect(*toleration.Effect)
+	}
+
+	switch result.Operator {
+	case "", k8sv1.TolerationOpEqual:
+		if result.Key == "" {
+			return result, errors.New("operator Equal requires a key")
+		}
+	case k8sv1.TolerationOpExists:
+		if result.Value != "" {
+			return result, errors.New("operator Exists doesn't allow a value")
+		}
+	default:
+		return result, fmt.Errorf("unsupported operator %s (supported: Equal, Exists)", result.Operator)
+	}
+
+	switch result.Effect {
+	case "", k8sv1.TaintEffectNoSchedule, k8sv1.TaintEffectPreferNoSchedule, k8sv1.TaintEffectNoExecute:
+	default:
+		return result, fmt.Errorf("unsupported effect %s (supported: NoSchedule, PreferNoSchedule, NoExecute)", result.Effect)
+	}
+
+	if result.TolerationSeconds != nil && result.Effect != k8sv1.TaintEffectNoExecute {
+		return result, errors.New("tolerationSeconds requires the effect NoExecute")
+	}
+
+	return result, nil
+}
+
+// getBuildCommand returns the kaniko executor command. The extra flags come last, so that they can override the flags
+// devspace sets (kaniko uses the last value of a flag)
+func (b *Builder) getBuildCommand(dockerfilePath string, options *types.ImageBuildOptions) []string {
+	imageDestination := b.ImageName + ":" + b.ImageTag
+	if b.RegistryURL != "" {
+		imageDestination = strings.TrimSuffix(b.RegistryURL, "/") + "/" + imageDestination
+	}
+
+	kanikoBuildCmd := []string{
+		"/kaniko/executor",
+		"--dockerfile=" + containerDockerfilePath + "/" + filepath.Base(dockerfilePath),
+		"--context=dir://" + containerBuildPath,
+		"--destination=" + imageDestination,
+		"--single-snapshot",
+	}
+
+	buildArgs := make([]string, 0, len(options.BuildArgs))
+	for key := range options.BuildArgs {
+		buildArgs = append(buildArgs, key)
+	}
+	sort.Strings(buildArgs)
+
+	for _, key := range buildArgs {
+		if value := options.BuildArgs[key]; value != nil {
+			kanikoBuildCmd = append(kanikoBuildCmd, "--build-arg", fmt.Sprintf("%v=%v", key, *value))
+		}
+	}
+
+	if options.Target != "" {
+		kanikoBuildCmd = append(kanikoBuildCmd, "--target="+options.Target)
+	}
+
+	// Without a cache repo, kaniko caches the layers in the repository <destination>/cache
+	if b.Cache && !options.NoCache {
+		kanikoBuildCmd = append(kanikoBuildCmd, "--cache=true")
+
+		if b.CacheRepo != "" {
+			kanikoBuildCmd = append(kanikoBuildCmd, "--cache-repo="+b.CacheRepo)
+		}
+	}
+
+	if b.CacheVolumeClaim != "" {
+		kanikoBuildCmd = append(kanikoBuildCmd, "--cache-dir="+containerCacheDir)
+	}
+
+	if b.allowInsecureRegistry {
+		kanikoBuildCmd = append(kanikoBuildCmd, "--insecure", "--skip-tls-verify")
+	}
+
+	return append(kanikoBuildCmd, b.Flags...)
 }
 
 // Authenticate authenticates kaniko for pushing to the RegistryURL (if username == "", it will try to get login data from local docker daemon)
@@ -74,16 +325,40 @@ func (b *Builder) Authenticate(username, password string, checkCredentialsStore
 		}
 	}
 
-	return nil, registry.CreatePullSecret(b.kubectl, b.BuildNamespace, b.RegistryURL, username, password, email, log.GetInstance())
+	return nil, registry.CreatePullSecret(b.kubectl, b.BuildNamespace, b.RegistryURL, username, password, email, b.log)
 }
 
 // BuildImage builds a dockerimage within a kaniko pod
-func (b *Builder) BuildImage(contextPath, dockerfilePath string, options *types.ImageBuildOptions) error {
+func (b *Builder) BuildImage(contextPath, dockerfilePath string, options *types.ImageBuildOptions, secrets *builder.BuildSecrets) error {
+	if secrets != nil && secrets.SSH {
+		return fmt.Errorf("SSH forwarding is not supported by kaniko, please use a build secret (e.g. a deploy key) instead")
+	}
+
 	pullSecretName := registry.GetRegistryAuthSecretName(b.RegistryURL)
 	if b.PullSecretName != "" {
 		pullSecretName = b.PullSecretName
 	}
 
+	hostNetwork := false
+	switch options.NetworkMode {
+	case "", "default", "bridge":
+	case "host":
+		hostNetwork = true
+	default:
+		return fmt.Errorf("Network mode %s is not supported by kaniko, only host is supported", options.NetworkMode)
+	}
+
+	// Pack the context before the build pod is started, so that a too large context fails fast
+	b.log.StartWait("Packing build context")
+	buildContext, err := createContextArchive(contextPath, dockerfilePath, b.MaxContextSize)
+	b.log.StopWait()
+	if err != nil {
+		return err
+	}
+	defer os.Remove(buildContext.Path)
+
+	b.log.Donef("Packed build context (%s, %s compressed)", units.HumanSize(float64(buildContext.ContextSize)), units.HumanSize(float64(buildContext.Size)))
+
 	randString, _ := randutil.GenerateRandomString(12)
 	buildID := strings.ToLower(randString)
 	buildPod := &k8sv1.Pod{
@@ -97,7 +372,7 @@ func (b *Builder) BuildImage(contextPath, dockerfilePath string, options *types.
 			Containers: []k8sv1.Container{
 				{
 					Name:            "kaniko",
-					Image:           "gcr.io/kaniko-project/executor:debug-5ac29a97734170a0547fea33b348dc7c328e2f8a",
+					Image:           b.ExecutorImage,
 					ImagePullPolicy: k8sv1.PullIfNotPresent,
 					Command: []string{
 						"/busybox/sleep",
@@ -105,6 +380,7 @@ func (b *Builder) BuildImage(contextPath, dockerfilePath string, options *types.
 					Args: []string{
 						"36000",
 					},
+					Resources: b.Resources,
 					VolumeMounts: []k8sv1.VolumeMount{
 						{
 							Name:      pullSecretName,
@@ -129,124 +405,211 @@ func (b *Builder) BuildImage(contextPath, dockerfilePath string, options *types.
 					},
 				},
 			},
-			RestartPolicy: k8sv1.RestartPolicyOnFailure,
+			RestartPolicy:      k8sv1.RestartPolicyOnFailure,
+			ServiceAccountName: b.ServiceAccount,
+			NodeSelector:       b.NodeSelector,
+			Tolerations:        b.Tolerations,
+			HostNetwork:        hostNetwork,
 		},
 	}
 
-	deleteBuildPod := func() {
-		gracePeriod := int64(3)
+	// Base images are read from the cache volume, which is filled by devspace build warm-cache. Several build pods
+	// may use the volume at once, so it is mounted read-only
+	if b.CacheVolumeClaim != "" {
+		buildPod.Spec.Containers[0].VolumeMounts = append(buildPod.Spec.Containers[0].VolumeMounts, k8sv1.VolumeMount{
+			Name:      cacheVolumeName,
+			MountPath: containerCacheDir,
+			ReadOnly:  true,
+		})
+		buildPod.Spec.Volumes = append(buildPod.Spec.Volumes, getCacheVolume(b.CacheVolumeClaim, true))
+	}
+
+	// Build secrets are mounted as files into the build pod, kaniko excludes mounted directories from the snapshots
+	var buildSecret *k8sv1.Secret
+	if secrets.IsEmpty() == false {
+		buildSecret, err = getBuildSecret(buildID, secrets)
+		if err != nil {
+			return err
+		}
 
-		deleteErr := b.kubectl.Core().Pods(b.BuildNamespace).Delete(buildPod.Name, &metav1.DeleteOptions{
-			GracePeriodSeconds: &gracePeriod,
+		buildPod.Spec.Containers[0].VolumeMounts = append(buildPod.Spec.Containers[0].VolumeMounts, k8sv1.VolumeMount{
+			Name:      buildSecret.Name,
+			MountPath: containerSecretsPath,
+			ReadOnly:  true,
+		})
+		buildPod.Spec.Volumes = append(buildPod.Spec.Volumes, k8sv1.Volume{
+			Name: buildSecret.Name,
+			VolumeSource: k8sv1.VolumeSource{
+				Secret: &k8sv1.SecretVolumeSource{
+					SecretName: buildSecret.Name,
+				},
+			},
 		})
+	}
+
+	// The build pod is deleted by the interrupt handler, the build timeout and after the build, which run in different
+	// goroutines. The names of the created resources are therefore guarded by a mutex
+	var (
+		createdMutex      sync.Mutex
+		createdPodName    string
+		createdSecretName string
+	)
+
+	deleteBuildPod := func() {
+		createdMutex.Lock()
+		defer createdMutex.Unlock()
+
+		if createdPodName != "" {
+			gracePeriod := int64(3)
+
+			deleteErr := b.kubectl.Core().Pods(b.BuildNamespace).Delete(createdPodName, &metav1.DeleteOptions{
+				GracePeriodSeconds: &gracePeriod,
+			})
+			if deleteErr != nil && kerrors.IsNotFound(deleteErr) == false {
+				b.log.Errorf("Failed to delete build pod: %s", deleteErr.Error())
+			}
+
+			createdPodName = ""
+		}
 
-		if deleteErr != nil {
-			log.Errorf("Failed to delete build pod: %s", deleteErr.Error())
+		if createdSecretName != "" {
+			deleteErr := b.kubectl.Core().Secrets(b.BuildNamespace).Delete(createdSecretName, &metav1.DeleteOptions{})
+			if deleteErr != nil && kerrors.IsNotFound(deleteErr) == false {
+				b.log.Errorf("Failed to delete build secrets: %v", deleteErr)
+			}
+
+			createdSecretName = ""
 		}
 	}
 
-	intr := interrupt.New(nil, deleteBuildPod)
+	// createBuildPod creates the build secret and pod while holding the mutex, so that an interrupt during the creation
+	// waits for it and deletes the created resources
+	createBuildPod := func() (*k8sv1.Pod, error) {
+		createdMutex.Lock()
+		defer createdMutex.Unlock()
+
+		if buildSecret != nil {
+			_, err := b.kubectl.Core().Secrets(b.BuildNamespace).Create(buildSecret)
+			if err != nil {
+				// Don't delete a secret with the same name that was not created by this build
+				return nil, fmt.Errorf("Unable to create build secrets: %v", err)
+			}
 
-	err := intr.Run(func() error {
-		buildPodCreated, buildPodCreateErr := b.kubectl.Core().Pods(b.BuildNamespace).Create(buildPod)
+			createdSecretName = buildSecret.Name
+		}
 
-		if buildPodCreateErr != nil {
-			return fmt.Errorf("Unable to create build pod: %s", buildPodCreateErr.Error())
+		buildPodCreated, err := b.kubectl.Core().Pods(b.BuildNamespace).Create(buildPod)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to create build pod: %s", err.Error())
 		}
 
-		readyWaitTime := 2 * 60 * time.Second
+		// Remember the name, so that the pod is deleted even if the status cannot be retrieved
+		createdPodName = buildPodCreated.Name
+
+		return buildPodCreated, nil
+	}
+
+	intr := interrupt.New(nil, deleteBuildPod)
+
+	err = intr.Run(func() error {
+		buildPodCreated, err := createBuildPod()
+		if err != nil {
+			return err
+		}
+
+		buildPod = buildPodCreated
+
+		readyWaitTime := b.StartTimeout
 		readyCheckInterval := 5 * time.Second
 		buildPodReady := false
 
-		log.StartWait("Waiting for kaniko build pod to start")
+		b.log.StartWait("Waiting for kaniko build pod to start")
 
 		for readyWaitTime > 0 {
-			buildPod, _ = b.kubectl.Core().Pods(b.BuildNamespace).Get(buildPodCreated.Name, metav1.GetOptions{})
-
-			if len(buildPod.Status.ContainerStatuses) > 0 && buildPod.Status.ContainerStatuses[0].Ready {
-				buildPodReady = true
-				break
+			pod, err := b.kubectl.Core().Pods(b.BuildNamespace).Get(buildPodCreated.Name, metav1.GetOptions{})
+			if err == nil {
+				buildPod = pod
+
+				if len(buildPod.Status.ContainerStatuses) > 0 {
+					containerStatus := buildPod.Status.ContainerStatuses[0]
+					if containerStatus.Ready {
+						buildPodReady = true
+						break
+					}
+
+					// Don't wait for the timeout if the executor image cannot be pulled
+					if containerStatus.State.Waiting != nil && isFatalWaitingReason(containerStatus.State.Waiting.Reason) {
+						b.log.StopWait()
+						return fmt.Errorf("Unable to start build pod: %s: %s", containerStatus.State.Waiting.Reason, containerStatus.State.Waiting.Message)
+					}
+				}
 			}
 
 			time.Sleep(readyCheckInterval)
 			readyWaitTime = readyWaitTime - readyCheckInterval
 		}
 
-		log.StopWait()
-		log.Done("Kaniko build pod started")
+		b.log.StopWait()
 
 		if !buildPodReady {
-			return fmt.Errorf("Unable to start build pod")
-		}
-		ignoreRules, ignoreRuleErr := ignoreutil.GetIgnoreRules(contextPath)
-
-		if ignoreRuleErr != nil {
-			return fmt.Errorf("Unable to parse .dockerignore files: %s", ignoreRuleErr.Error())
+			return fmt.Errorf("Unable to start build pod within %v", b.StartTimeout)
 		}
 
+		b.log.Done("Kaniko build pod started")
 		buildContainer := &buildPod.Spec.Containers[0]
 
-		log.StartWait("Uploading files to build container")
-		err := synctool.CopyToContainer(b.kubectl, buildPod, buildContainer, contextPath, "/src", ignoreRules)
-
-		if err != nil {
-			return fmt.Errorf("Error uploading files to container: %s", err.Error())
-		}
-		err = synctool.CopyToContainer(b.kubectl, buildPod, buildContainer, dockerfilePath, "/src", ignoreRules)
+		b.log.StartWait("Uploading build context")
+		err = uploadContextArchive(b.kubectl, buildPod, buildContainer.Name, buildContext, b.log)
+		b.log.StopWait()
 
 		if err != nil {
-			return fmt.Errorf("Error uploading files to container: %s", err.Error())
+			return fmt.Errorf("Error uploading build context to container: %v", err)
 		}
-		log.StopWait()
-		log.Done("Uploaded files to container")
 
-		log.StartWait("Building container image")
+		b.log.Done("Uploaded build context to container")
 
-		imageDestination := b.ImageName + ":" + b.ImageTag
+		b.log.StartWait("Building container image")
 
-		if b.RegistryURL != "" {
-			imageDestination = strings.TrimSuffix(b.RegistryURL, "/") + "/" + imageDestination
-		}
-		containerBuildPath := "/src"
 		exitChannel := make(chan error)
-		kanikoBuildCmd := []string{
-			"/kaniko/executor",
-			"--dockerfile=" + containerBuildPath + "/Dockerfile",
-			"--context=dir://" + containerBuildPath,
-			"--destination=" + imageDestination,
-			"--single-snapshot",
-		}
-
-		for key, value := range options.BuildArgs {
-			newKanikoArg := fmt.Sprintf("%v=%v", key, *value)
-			kanikoBuildCmd = append(kanikoBuildCmd, "--build-arg", newKanikoArg)
-		}
-
-		if !options.NoCache {
-			kanikoBuildCmd = append(kanikoBuildCmd, "--cache=true", "--cache-repo="+b.PreviousImageTag)
-		}
-
-		if b.allowInsecureRegistry {
-			kanikoBuildCmd = append(kanikoBuildCmd, "--insecure", "--skip-tls-verify")
-		}
+		kanikoBuildCmd := b.getBuildCommand(dockerfilePath, options)
 
 		stdoutReader, stdoutWriter, _ := os.Pipe()
 		stderrReader, stderrWriter, _ := os.Pipe()
 
 		go func() {
-			exitChannel <- kubectl.ExecStream(b.kubectl, buildPod, buildContainer.Name, kanikoBuildCmd, false, nil, stdoutWriter, stderrWriter)
+			err := kubectl.ExecStream(b.kubectl, buildPod, buildContainer.Name, kanikoBuildCmd, false, nil, stdoutWriter, stderrWriter)
+
+			stdoutWriter.Close()
+			stderrWriter.Close()
+			exitChannel <- err
 		}()
 
-		lastKanikoOutput := formatKanikoOutput(stdoutReader, stderrReader)
+		// Deleting the build pod stops the build and closes the output streams
+		timedOut := make(chan bool, 1)
+		if b.BuildTimeout > 0 {
+			timer := time.AfterFunc(b.BuildTimeout, func() {
+				timedOut <- true
+				deleteBuildPod()
+			})
+			defer timer.Stop()
+		}
+
+		lastKanikoOutput := formatKanikoOutput(stdoutReader, stderrReader, b.Verbose, b.log)
 		exitError := <-exitChannel
 
-		log.StopWait()
+		b.log.StopWait()
+
+		select {
+		case <-timedOut:
+			return fmt.Errorf("Build timed out after %v, Last Kaniko Output: %s", b.BuildTimeout, lastKanikoOutput)
+		default:
+		}
 
 		if exitError != nil {
 			return fmt.Errorf("Error: %s, Last Kaniko Output: %s", exitError.Error(), lastKanikoOutput)
 		}
 
-		log.Done("Done building image")
+		b.log.Done("Done building image")
 
 		return nil
 	})
@@ -258,6 +621,40 @@ func (b *Builder) BuildImage(contextPath, dockerfilePath string, options *types.
 	return nil
 }
 
+// getBuildSecret returns a secret that contains the content of the build secret files with the secret ids as keys
+func getBuildSecret(buildID string, secrets *builder.BuildSecrets) (*k8sv1.Secret, error) {
+	data := map[string][]byte{}
+	for id, file := range secrets.Files {
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to read build secret %s: %v", id, err)
+		}
+
+		data[id] = content
+	}
+
+	return &k8sv1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "devspace-build-secrets-" + buildID,
+			Labels: map[string]string{
+				"devspace-build-id": buildID,
+			},
+		},
+		Type: k8sv1.SecretTypeOpaque,
+		Data: data,
+	}, nil
+}
+
+// isFatalWaitingReason returns true if a container with this waiting reason won't start without user interaction
+func isFatalWaitingReason(reason string) bool {
+	switch reason {
+	case "ErrImagePull", "ImagePullBackOff", "InvalidImageName", "CreateContainerConfigError":
+		return true
+	}
+
+	return false
+}
+
 // PushImage is required to implement builder.Interface
 func (b *Builder) PushImage() error {
 	return nil
@@ -0,0 +1,277 @@
+package kaniko
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/covexo/devspace/pkg/devspace/config/configutil"
+	"github.com/covexo/devspace/pkg/devspace/config/v1"
+	"github.com/covexo/devspace/pkg/util/log"
+	"github.com/docker/docker/api/types"
+	k8sv1 "k8s.io/api/core/v1"
+)
+
+func TestNewBuilder(t *testing.T) {
+	tolerationSeconds := int64(60)
+
+	testCases := []struct {
+		name         string
+		kanikoConfig *v1.KanikoConfig
+		expectedErr  bool
+		check        func(builder *Builder) string
+	}{
+		{
+			name: "defaults",
+			check: func(builder *Builder) string {
+				if builder.Cache == false || builder.ExecutorImage != DefaultExecutorImage || builder.StartTimeout != DefaultStartTimeout || builder.BuildTimeout != 0 || builder.MaxContextSize != DefaultMaxContextSize {
+					return "unexpected defaults"
+				}
+
+				return ""
+			},
+		},
+		{
+			name: "resources",
+			kanikoConfig: &v1.KanikoConfig{
+				Resources: &v1.ResourceConfig{
+					Requests: &map[string]*string{
+						"cpu":    configutil.String("500m"),
+						"memory": configutil.String("1Gi"),
+					},
+					Limits: &map[string]*string{
+						"memory": configutil.String("4Gi"),
+					},
+				},
+			},
+			check: func(builder *Builder) string {
+				cpu := builder.Resources.Requests[k8sv1.ResourceCPU]
+				memory := builder.Resources.Limits[k8sv1.ResourceMemory]
+				if cpu.String() != "500m" || memory.String() != "4Gi" || len(builder.Resources.Requests) != 2 || len(builder.Resources.Limits) != 1 {
+					return "unexpected resources"
+				}
+
+				return ""
+			},
+		},
+		{
+			name: "invalid resource request",
+			kanikoConfig: &v1.KanikoConfig{
+				Resources: &v1.ResourceConfig{
+					Requests: &map[string]*string{
+						"cpu": configutil.String("half a core"),
+					},
+				},
+			},
+			expectedErr: true,
+		},
+		{
+			name: "invalid resource limit",
+			kanikoConfig: &v1.KanikoConfig{
+				Resources: &v1.ResourceConfig{
+					Limits: &map[string]*string{
+						"memory": configutil.String("4GB"),
+					},
+				},
+			},
+			expectedErr: true,
+		},
+		{
+			name: "resource without quantity",
+			kanikoConfig: &v1.KanikoConfig{
+				Resources: &v1.ResourceConfig{
+					Limits: &map[string]*string{
+						"memory": nil,
+					},
+				},
+			},
+			expectedErr: true,
+		},
+		{
+			name: "tolerations",
+			kanikoConfig: &v1.KanikoConfig{
+				Tolerations: &[]*v1.TolerationConfig{
+					{
+						Key:    configutil.String("dedicated"),
+						Value:  configutil.String("builds"),
+						Effect: configutil.String("NoSchedule"),
+					},
+					{
+						Operator:          configutil.String("Exists"),
+						Effect:            configutil.String("NoExecute"),
+						TolerationSeconds: &tolerationSeconds,
+					},
+				},
+			},
+			check: func(builder *Builder) string {
+				if len(builder.Tolerations) != 2 {
+					return "expected 2 tolerations"
+				}
+				if builder.Tolerations[0].Key != "dedicated" || builder.Tolerations[0].Value != "builds" || builder.Tolerations[0].Effect != k8sv1.TaintEffectNoSchedule {
+					return "unexpected first toleration"
+				}
+				if builder.Tolerations[1].Operator != k8sv1.TolerationOpExists || *builder.Tolerations[1].TolerationSeconds != 60 {
+					return "unexpected second toleration"
+				}
+
+				return ""
+			},
+		},
+		{
+			name: "toleration with unknown operator",
+			kanikoConfig: &v1.KanikoConfig{
+				Tolerations: &[]*v1.TolerationConfig{
+					{Key: configutil.String("dedicated"), Operator: configutil.String("In")},
+				},
+			},
+			expectedErr: true,
+		},
+		{
+			name: "toleration with Exists and value",
+			kanikoConfig: &v1.KanikoConfig{
+				Tolerations: &[]*v1.TolerationConfig{
+					{Key: configutil.String("dedicated"), Operator: configutil.String("Exists"), Value: configutil.String("builds")},
+				},
+			},
+			expectedErr: true,
+		},
+		{
+			name: "toleration with Equal and without key",
+			kanikoConfig: &v1.KanikoConfig{
+				Tolerations: &[]*v1.TolerationConfig{
+					{Value: configutil.String("builds")},
+				},
+			},
+			expectedErr: true,
+		},
+		{
+			name: "toleration with unknown effect",
+			kanikoConfig: &v1.KanikoConfig{
+				Tolerations: &[]*v1.TolerationConfig{
+					{Key: configutil.String("dedicated"), Effect: configutil.String("NoBuilds")},
+				},
+			},
+			expectedErr: true,
+		},
+		{
+			name: "toleration seconds without NoExecute",
+			kanikoConfig: &v1.KanikoConfig{
+				Tolerations: &[]*v1.TolerationConfig{
+					{Key: configutil.String("dedicated"), Effect: configutil.String("NoSchedule"), TolerationSeconds: &tolerationSeconds},
+				},
+			},
+			expectedErr: true,
+		},
+		{
+			name: "timeouts",
+			kanikoConfig: &v1.KanikoConfig{
+				StartTimeout: configutil.Int(300),
+				BuildTimeout: configutil.Int(1800),
+			},
+			check: func(builder *Builder) string {
+				if builder.StartTimeout != 5*time.Minute || builder.BuildTimeout != 30*time.Minute {
+					return "unexpected timeouts"
+				}
+
+				return ""
+			},
+		},
+		{
+			name: "non-positive timeouts keep the defaults",
+			kanikoConfig: &v1.KanikoConfig{
+				StartTimeout: configutil.Int(0),
+				BuildTimeout: configutil.Int(-1),
+			},
+			check: func(builder *Builder) string {
+				if builder.StartTimeout != DefaultStartTimeout || builder.BuildTimeout != 0 {
+					return "unexpected timeouts"
+				}
+
+				return ""
+			},
+		},
+		{
+			name: "max context size",
+			kanikoConfig: &v1.KanikoConfig{
+				MaxContextSize: configutil.String("200Mi"),
+			},
+			check: func(builder *Builder) string {
+				if builder.MaxContextSize != 200*1024*1024 {
+					return "unexpected max context size"
+				}
+
+				return ""
+			},
+		},
+		{
+			name: "invalid max context size",
+			kanikoConfig: &v1.KanikoConfig{
+				MaxContextSize: configutil.String("200 MB"),
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		builder, err := NewBuilder("", "user/app", "v1", "default", testCase.kanikoConfig, nil, nil, false, log.Discard)
+		if testCase.expectedErr {
+			if err == nil {
+				t.Fatalf("Test case %s: expected an error", testCase.name)
+			}
+
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Test case %s: %v", testCase.name, err)
+		}
+
+		if testCase.check != nil {
+			if message := testCase.check(builder); message != "" {
+				t.Fatalf("Test case %s: %s", testCase.name, message)
+			}
+		}
+	}
+}
+
+func TestGetBuildCommand(t *testing.T) {
+	builder, err := NewBuilder("registry.local:5000", "user/app", "v1", "default", &v1.KanikoConfig{
+		CacheRepo: configutil.String("registry.local:5000/cache"),
+		Flags: &[]*string{
+			configutil.String("--cache=false"),
+			configutil.String("--single-snapshot=false"),
+		},
+	}, nil, nil, true, log.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	command := builder.getBuildCommand("/project/Dockerfile.dev", &types.ImageBuildOptions{
+		BuildArgs: map[string]*string{
+			"VERSION": configutil.String("1.0"),
+			"ARCH":    configutil.String("amd64"),
+		},
+		Target: "dev",
+	})
+
+	expected := []string{
+		"/kaniko/executor",
+		"--dockerfile=" + containerDockerfilePath + "/Dockerfile.dev",
+		"--context=dir://" + containerBuildPath,
+		"--destination=registry.local:5000/user/app:v1",
+		"--single-snapshot",
+		"--build-arg", "ARCH=amd64",
+		"--build-arg", "VERSION=1.0",
+		"--target=dev",
+		"--cache=true",
+		"--cache-repo=registry.local:5000/cache",
+		"--insecure",
+		"--skip-tls-verify",
+		// The extra flags come last, so that they override the flags above
+		"--cache=false",
+		"--single-snapshot=false",
+	}
+
+	if strings.Join(command, " ") != strings.Join(expected, " ") {
+		t.Fatalf("Expected command\n%s\ngot\n%s", strings.Join(expected, " "), strings.Join(command, " "))
+	}
+}
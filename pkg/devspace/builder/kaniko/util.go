@@ -15,8 +15,11 @@ type OutputFormat struct {
 	Replacement string
 }
 
-func formatKanikoOutput(stdout io.ReadCloser, stderr io.ReadCloser) string {
+// formatKanikoOutput streams the output of kaniko line by line and returns the last line. Kaniko messages that are not
+// build steps (e.g. snapshot messages) are logged as debug messages unless verbose is true
+func formatKanikoOutput(stdout io.ReadCloser, stderr io.ReadCloser, verbose bool, log log.Logger) string {
 	wg := &sync.WaitGroup{}
+	lineMutex := sync.Mutex{}
 	lastLine := ""
 	outputFormats := []OutputFormat{
 		{
@@ -61,6 +64,9 @@ func formatKanikoOutput(stdout io.ReadCloser, stderr io.ReadCloser) string {
 	buildPrefix := "build >"
 
 	printFormattedOutput := func(originalLine string) {
+		lineMutex.Lock()
+		defer lineMutex.Unlock()
+
 		line := []byte(originalLine)
 
 		for _, outputFormat := range outputFormats {
@@ -71,8 +77,10 @@ func formatKanikoOutput(stdout io.ReadCloser, stderr io.ReadCloser) string {
 
 		if len(line) != len(originalLine) {
 			log.Done(buildPrefix + lineString)
-		} else if kanikoLogRegex.Match(line) == false {
+		} else if kanikoLogRegex.Match(line) == false || verbose {
 			log.Info(buildPrefix + ">> " + lineString)
+		} else {
+			log.Debug(buildPrefix + ">> " + lineString)
 		}
 
 		lastLine = string(kanikoLogRegex.ReplaceAll([]byte(originalLine), []byte("$3")))
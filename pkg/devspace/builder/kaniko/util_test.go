@@ -0,0 +1,62 @@
+package kaniko
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/covexo/devspace/pkg/util/log"
+)
+
+const testKanikoOutput = `time="2018-11-20T10:00:00Z" level=info msg="Downloading base image node:10"
+time="2018-11-20T10:00:01Z" level=info msg="Taking snapshot of files..."
+time="2018-11-20T10:00:02Z" level=info msg="cmd: copy [package.json]"
+npm WARN deprecated request@2.88.0
+time="2018-11-20T10:00:03Z" level=info msg="Pushed image to 1 destinations"
+`
+
+func TestFormatKanikoOutput(t *testing.T) {
+	testCases := []struct {
+		name            string
+		verbose         bool
+		expectedConsole string
+	}{
+		{
+			name:            "snapshot messages as debug messages",
+			expectedConsole: "build > FROM node:10\nbuild > COPY package.json\nbuild >>> npm WARN deprecated request@2.88.0\n",
+		},
+		{
+			name:    "verbose",
+			verbose: true,
+			expectedConsole: "build > FROM node:10\n" +
+				"build >>> time=\"2018-11-20T10:00:01Z\" level=info msg=\"Taking snapshot of files...\"\n" +
+				"build > COPY package.json\n" +
+				"build >>> npm WARN deprecated request@2.88.0\n" +
+				"build >>> time=\"2018-11-20T10:00:03Z\" level=info msg=\"Pushed image to 1 destinations\"\n",
+		},
+	}
+
+	for _, testCase := range testCases {
+		console := &bytes.Buffer{}
+		buildLog := &bytes.Buffer{}
+		logger := log.NewBuildLogger(log.NewBuildLogger(log.Discard, console), buildLog)
+
+		stdout := ioutil.NopCloser(strings.NewReader(testKanikoOutput))
+		stderr := ioutil.NopCloser(strings.NewReader(""))
+
+		lastLine := formatKanikoOutput(stdout, stderr, testCase.verbose, logger)
+		if lastLine != "Pushed image to 1 destinations" {
+			t.Fatalf("Test case %s: expected last line Pushed image to 1 destinations, got %s", testCase.name, lastLine)
+		}
+
+		if console.String() != testCase.expectedConsole {
+			t.Fatalf("Test case %s: expected console output\n%s\ngot\n%s", testCase.name, testCase.expectedConsole, console.String())
+		}
+
+		// The build log always contains the complete output
+		if strings.Count(buildLog.String(), "\n") != 5 || strings.Contains(buildLog.String(), "Taking snapshot of files...") == false {
+			t.Fatalf("Test case %s: expected all lines in the build log, got\n%s", testCase.name, buildLog.String())
+		}
+	}
+}
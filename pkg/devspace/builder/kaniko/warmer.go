@@ -0,0 +1,200 @@
+package kaniko
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/covexo/devspace/pkg/devspace/kubectl"
+	"github.com/covexo/devspace/pkg/util/log"
+	k8sv1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/kubernetes/pkg/util/interrupt"
+)
+
+// DefaultWarmerImage is the image of the pod that pulls base images into the cache volume
+const DefaultWarmerImage = "gcr.io/kaniko-project/warmer:v0.9.0"
+
+// DefaultWarmTimeout is the time the warmer pod may take to pull the base images after it started
+const DefaultWarmTimeout = 30 * time.Minute
+
+// getCacheVolume returns the volume of the base image cache
+func getCacheVolume(volumeClaim string, readOnly bool) k8sv1.Volume {
+	return k8sv1.Volume{
+		Name: cacheVolumeName,
+		VolumeSource: k8sv1.VolumeSource{
+			PersistentVolumeClaim: &k8sv1.PersistentVolumeClaimVolumeSource{
+				ClaimName: volumeClaim,
+				ReadOnly:  readOnly,
+			},
+		},
+	}
+}
+
+// WarmCache pulls the images into the base image cache volume with the kaniko warmer, so that the build pods don't
+// pull the base images for every build. The pullSecret is used to pull private images, it is optional so that public
+// base images can be cached before the first build created the registry pull secret
+func WarmCache(client *kubernetes.Clientset, namespace, volumeClaim, pullSecret, warmerImage string, images []string, log log.Logger) error {
+	if warmerImage == "" {
+		warmerImage = DefaultWarmerImage
+	}
+
+	args := []string{"--cache-dir=" + containerCacheDir}
+	for _, image := range images {
+		args = append(args, "--image="+image)
+	}
+
+	warmerPod := &k8sv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "devspace-cache-warmer-",
+		},
+		Spec: k8sv1.PodSpec{
+			Containers: []k8sv1.Container{
+				{
+					Name:            "warmer",
+					Image:           warmerImage,
+					ImagePullPolicy: k8sv1.PullIfNotPresent,
+					Args:            args,
+					VolumeMounts: []k8sv1.VolumeMount{
+						{
+							Name:      cacheVolumeName,
+							MountPath: containerCacheDir,
+						},
+					},
+				},
+			},
+			Volumes: []k8sv1.Volume{
+				getCacheVolume(volumeClaim, false),
+			},
+			RestartPolicy: k8sv1.RestartPolicyNever,
+		},
+	}
+
+	if pullSecret != "" {
+		optional := true
+
+		warmerPod.Spec.Containers[0].VolumeMounts = append(warmerPod.Spec.Containers[0].VolumeMounts, k8sv1.VolumeMount{
+			Name:      pullSecret,
+			MountPath: "/kaniko/.docker",
+		})
+		warmerPod.Spec.Volumes = append(warmerPod.Spec.Volumes, k8sv1.Volume{
+			Name: pullSecret,
+			VolumeSource: k8sv1.VolumeSource{
+				Secret: &k8sv1.SecretVolumeSource{
+					SecretName: pullSecret,
+					Items: []k8sv1.KeyToPath{
+						{
+							Key:  k8sv1.DockerConfigJsonKey,
+							Path: "config.json",
+						},
+					},
+					Optional: &optional,
+				},
+			},
+		})
+	}
+
+	deleteWarmerPod := func() {
+		if warmerPod.Name == "" {
+			return
+		}
+
+		gracePeriod := int64(3)
+
+		err := client.Core().Pods(namespace).Delete(warmerPod.Name, &metav1.DeleteOptions{
+			GracePeriodSeconds: &gracePeriod,
+		})
+		if err != nil && kerrors.IsNotFound(err) == false {
+			log.Errorf("Failed to delete cache warmer pod: %v", err)
+		}
+	}
+
+	intr := interrupt.New(nil, deleteWarmerPod)
+
+	return intr.Run(func() error {
+		createdPod, err := client.Core().Pods(namespace).Create(warmerPod)
+		if err != nil {
+			return fmt.Errorf("Unable to create cache warmer pod: %v", err)
+		}
+
+		warmerPod = createdPod
+
+		log.StartWait(fmt.Sprintf("Pulling %d base images into volume %s", len(images), volumeClaim))
+		err = waitForWarmerPod(client, namespace, createdPod.Name)
+		log.StopWait()
+
+		logs, logsErr := kubectl.Logs(client, namespace, createdPod.Name, "warmer", false, nil)
+		if logsErr == nil {
+			log.Write([]byte(logs))
+		}
+
+		if err != nil {
+			return err
+		}
+
+		log.Donef("Pulled %d base images into volume %s", len(images), volumeClaim)
+		return nil
+	})
+}
+
+// waitForWarmerPod waits until the warmer pod terminated. The pod has to start within DefaultStartTimeout,
+// e.g. a volume claim that cannot be bound fails the pod, and has to finish within DefaultWarmTimeout after it started
+func waitForWarmerPod(client *kubernetes.Clientset, namespace, name string) error {
+	startWaitTime := DefaultStartTimeout
+	warmWaitTime := DefaultWarmTimeout
+	checkInterval := 2 * time.Second
+
+	for {
+		pod, err := client.Core().Pods(namespace).Get(name, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("Error retrieving cache warmer pod: %v", err)
+		}
+
+		switch pod.Status.Phase {
+		case k8sv1.PodSucceeded:
+			return nil
+		case k8sv1.PodFailed:
+			return fmt.Errorf("Cache warmer pod failed: %s", getTerminationMessage(pod))
+		case k8sv1.PodPending:
+			if len(pod.Status.ContainerStatuses) > 0 {
+				waiting := pod.Status.ContainerStatuses[0].State.Waiting
+				if waiting != nil && isFatalWaitingReason(waiting.Reason) {
+					return fmt.Errorf("Unable to start cache warmer pod: %s: %s", waiting.Reason, waiting.Message)
+				}
+			}
+
+			if startWaitTime <= 0 {
+				return fmt.Errorf("Unable to start cache warmer pod within %v", DefaultStartTimeout)
+			}
+
+			startWaitTime = startWaitTime - checkInterval
+		default:
+			// A registry that doesn't respond would block the warmer forever
+			if warmWaitTime <= 0 {
+				return fmt.Errorf("Cache warmer pod did not finish within %v", DefaultWarmTimeout)
+			}
+
+			warmWaitTime = warmWaitTime - checkInterval
+		}
+
+		time.Sleep(checkInterval)
+	}
+}
+
+func getTerminationMessage(pod *k8sv1.Pod) string {
+	messages := []string{}
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		if containerStatus.State.Terminated != nil {
+			terminated := containerStatus.State.Terminated
+			messages = append(messages, strings.TrimSpace(fmt.Sprintf("exit code %d %s", terminated.ExitCode, terminated.Message)))
+		}
+	}
+
+	if len(messages) == 0 {
+		return pod.Status.Reason
+	}
+
+	return strings.Join(messages, ", ")
+}
@@ -1,7 +1,9 @@
 package configutil
 
 import (
+	"fmt"
 	"io/ioutil"
+	"os"
 
 	"github.com/covexo/devspace/pkg/devspace/config/v1"
 	yaml "gopkg.in/yaml.v2"
@@ -15,3 +17,28 @@ func loadConfig(config *v1.Config, path string) error {
 
 	return yaml.UnmarshalStrict(yamlFileContent, config)
 }
+
+// CheckConfig loads the config and the overwrite config like GetConfig, but returns an error instead of exiting if they are invalid
+func CheckConfig() error {
+	configRaw := makeConfig()
+
+	err := loadConfig(configRaw, ConfigPath)
+	if err != nil {
+		return fmt.Errorf("Error loading %s: %v", ConfigPath, err)
+	}
+	if configRaw.Version == nil || *configRaw.Version != CurrentConfigVersion {
+		return fmt.Errorf("Config %s is out of date (expected version %s)", ConfigPath, CurrentConfigVersion)
+	}
+
+	if OverwriteConfigPath != "" {
+		_, err := os.Stat(OverwriteConfigPath)
+		if err == nil {
+			err = loadConfig(makeConfig(), OverwriteConfigPath)
+			if err != nil {
+				return fmt.Errorf("Error loading %s: %v", OverwriteConfigPath, err)
+			}
+		}
+	}
+
+	return nil
+}
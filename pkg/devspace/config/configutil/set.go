@@ -9,3 +9,8 @@ func String(val string) *string {
 func Bool(val bool) *bool {
 	return &val
 }
+
+//Int returns a pointer to an int variable
+func Int(val int) *int {
+	return &val
+}
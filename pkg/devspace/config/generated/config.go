@@ -12,8 +12,7 @@ import (
 type Config struct {
 	HelmOverrideTimestamps map[string]int64  `yaml:"helmOverrideTimestamps"`
 	HelmChartHashs         map[string]string `yaml:"helmChartHashs"`
-	DockerfileTimestamps   map[string]int64  `yaml:"dockerfileTimestamps"`
-	DockerContextPaths     map[string]string `yaml:"dockerContextPaths"`
+	ImageFingerprints      map[string]string `yaml:"imageFingerprints"`
 	ImageTags              map[string]string `yaml:"imageTags"`
 }
 
@@ -27,8 +26,7 @@ func LoadConfig() (*Config, error) {
 	data, err := ioutil.ReadFile(filepath.Join(workdir, ConfigPath))
 	if err != nil {
 		return &Config{
-			DockerfileTimestamps:   make(map[string]int64),
-			DockerContextPaths:     make(map[string]string),
+			ImageFingerprints:      make(map[string]string),
 			ImageTags:              make(map[string]string),
 			HelmChartHashs:         make(map[string]string),
 			HelmOverrideTimestamps: make(map[string]int64),
@@ -47,11 +45,8 @@ func LoadConfig() (*Config, error) {
 	if config.HelmOverrideTimestamps == nil {
 		config.HelmOverrideTimestamps = make(map[string]int64)
 	}
-	if config.DockerfileTimestamps == nil {
-		config.DockerfileTimestamps = make(map[string]int64)
-	}
-	if config.DockerContextPaths == nil {
-		config.DockerContextPaths = make(map[string]string)
+	if config.ImageFingerprints == nil {
+		config.ImageFingerprints = make(map[string]string)
 	}
 	if config.ImageTags == nil {
 		config.ImageTags = make(map[string]string)
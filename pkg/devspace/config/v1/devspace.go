@@ -2,12 +2,14 @@ package v1
 
 //DevSpaceConfig defines the devspace deployment
 type DevSpaceConfig struct {
-	Terminal    *Terminal                `yaml:"terminal"`
-	AutoReload  *AutoReloadPathsConfig   `yaml:"autoReload,omitempty"`
-	Services    *[]*ServiceConfig        `yaml:"services,omitempty"`
-	Deployments *[]*DeploymentConfig     `yaml:"deployments,omitempty"`
-	Ports       *[]*PortForwardingConfig `yaml:"ports"`
-	Sync        *[]*SyncConfig           `yaml:"sync"`
+	Terminal    *Terminal                  `yaml:"terminal"`
+	AutoReload  *AutoReloadPathsConfig     `yaml:"autoReload,omitempty"`
+	Services    *[]*ServiceConfig          `yaml:"services,omitempty"`
+	Deployments *[]*DeploymentConfig       `yaml:"deployments,omitempty"`
+	Ports       *[]*PortForwardingConfig   `yaml:"ports"`
+	Sync        *[]*SyncConfig             `yaml:"sync"`
+	Proxy       *ProxyConfig               `yaml:"proxy,omitempty"`
+	Commands    *map[string]*CommandConfig `yaml:"commands,omitempty"`
 }
 
 // AutoReloadPathsConfig defines the struct for auto reloading devspace with additional paths
@@ -15,6 +17,17 @@ type AutoReloadPathsConfig struct {
 	Paths *[]*string `yaml:"paths,omitempty"`
 }
 
+// CommandConfig defines a named command that can be executed in a devspace container with devspace run
+type CommandConfig struct {
+	Command       *[]*string          `yaml:"command"`
+	Service       *string             `yaml:"service,omitempty"`
+	Namespace     *string             `yaml:"namespace,omitempty"`
+	LabelSelector *map[string]*string `yaml:"labelSelector,omitempty"`
+	ContainerName *string             `yaml:"containerName,omitempty"`
+	WorkDir       *string             `yaml:"workDir,omitempty"`
+	Env           *map[string]*string `yaml:"env,omitempty"`
+}
+
 // ServiceConfig defines the kubernetes services that belong to the devspace
 type ServiceConfig struct {
 	Name          *string             `yaml:"name,omitempty"`
@@ -22,6 +35,18 @@ type ServiceConfig struct {
 	ResourceType  *string             `yaml:"resourceType,omitempty"`
 	LabelSelector *map[string]*string `yaml:"labelSelector"`
 	ContainerName *string             `yaml:"containerName"`
+	DevMode       *DevModeConfig      `yaml:"devMode,omitempty"`
+}
+
+// DevModeConfig defines how an existing deployment or statefulset of a service is patched for development
+type DevModeConfig struct {
+	Disabled   *bool      `yaml:"disabled,omitempty"`
+	Kind       *string    `yaml:"kind,omitempty"`
+	Name       *string    `yaml:"name"`
+	Image      *string    `yaml:"image,omitempty"`
+	Command    *[]*string `yaml:"command,omitempty"`
+	Args       *[]*string `yaml:"args,omitempty"`
+	VolumePath *string    `yaml:"volumePath,omitempty"`
 }
 
 // PortForwardingConfig defines the ports for a port forwarding to a DevSpace
@@ -35,9 +60,27 @@ type PortForwardingConfig struct {
 
 // PortMapping defines the ports for a PortMapping
 type PortMapping struct {
-	LocalPort   *int    `yaml:"localPort"`
-	RemotePort  *int    `yaml:"remotePort"`
-	BindAddress *string `yaml:"bindAddress"`
+	LocalPort   *int             `yaml:"localPort"`
+	RemotePort  *int             `yaml:"remotePort"`
+	BindAddress *string          `yaml:"bindAddress"`
+	Probe       *PortProbeConfig `yaml:"probe,omitempty"`
+}
+
+// PortProbeConfig defines a health probe that is executed against the local end of a port forwarding
+type PortProbeConfig struct {
+	Type           *string `yaml:"type,omitempty"`
+	Path           *string `yaml:"path,omitempty"`
+	ExpectedStatus *int    `yaml:"expectedStatus,omitempty"`
+	Interval       *int    `yaml:"interval,omitempty"`
+	Timeout        *int    `yaml:"timeout,omitempty"`
+}
+
+// ProxyConfig defines the local reverse proxy that routes hostnames to forwarded ports
+type ProxyConfig struct {
+	Disabled    *bool   `yaml:"disabled,omitempty"`
+	Domain      *string `yaml:"domain,omitempty"`
+	Port        *int    `yaml:"port,omitempty"`
+	BindAddress *string `yaml:"bindAddress,omitempty"`
 }
 
 // SyncConfig defines the paths for a SyncFolder
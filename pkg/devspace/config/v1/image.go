@@ -4,28 +4,73 @@ package v1
 type ImageConfig struct {
 	Name             *string           `yaml:"name"`
 	Tag              *string           `yaml:"tag"`
+	TagStrategy      *string           `yaml:"tagStrategy,omitempty"`
 	Registry         *string           `yaml:"registry"`
 	CreatePullSecret *bool             `yaml:"createPullSecret,omitempty"`
 	SkipPush         *bool             `yaml:"skipPush"`
+	SideLoad         *SideLoadConfig   `yaml:"sideLoad,omitempty"`
 	AutoReload       *AutoReloadConfig `yaml:"autoReload"`
 	Build            *BuildConfig      `yaml:"build"`
+	DependsOn        *[]*string        `yaml:"dependsOn,omitempty"`
+}
+
+// SideLoadConfig tells the DevSpace CLI to load the built image directly into the nodes of a local kind or k3d cluster instead of pushing it
+type SideLoadConfig struct {
+	Disabled    *bool   `yaml:"disabled,omitempty"`
+	Cluster     *string `yaml:"cluster,omitempty"`
+	ClusterName *string `yaml:"clusterName,omitempty"`
+}
+
+// BuildSettings defines how all images are built
+type BuildSettings struct {
+	Parallelism *int  `yaml:"parallelism,omitempty"`
+	Verbose     *bool `yaml:"verbose,omitempty"`
 }
 
 //BuildConfig defines the build process for an image
 type BuildConfig struct {
-	Disabled       *bool         `yaml:"disabled,omitempty"`
-	ContextPath    *string       `yaml:"contextPath"`
-	DockerfilePath *string       `yaml:"dockerfilePath"`
-	Kaniko         *KanikoConfig `yaml:"kaniko,omitempty"`
-	Docker         *DockerConfig `yaml:"docker,omitempty"`
-	Options        *BuildOptions `yaml:"options,omitempty"`
+	Disabled             *bool         `yaml:"disabled,omitempty"`
+	ContextPath          *string       `yaml:"contextPath"`
+	DockerfilePath       *string       `yaml:"dockerfilePath"`
+	Kaniko               *KanikoConfig `yaml:"kaniko,omitempty"`
+	Docker               *DockerConfig `yaml:"docker,omitempty"`
+	Custom               *CustomConfig `yaml:"custom,omitempty"`
+	Options              *BuildOptions `yaml:"options,omitempty"`
+	CheckBaseImageDigest *bool         `yaml:"checkBaseImageDigest,omitempty"`
 }
 
-// KanikoConfig tells the DevSpace CLI to build with Docker on Minikube or on localhost
+// KanikoConfig tells the DevSpace CLI to build with kaniko in a pod of the cluster
 type KanikoConfig struct {
-	Cache      *bool   `yaml:"cache"`
-	Namespace  *string `yaml:"namespace,omitempty"`
-	PullSecret *string `yaml:"pullSecret,omitempty"`
+	Cache            *bool                `yaml:"cache"`
+	CacheRepo        *string              `yaml:"cacheRepo,omitempty"`
+	CacheVolumeClaim *string              `yaml:"cacheVolumeClaim,omitempty"`
+	WarmerImage      *string              `yaml:"warmerImage,omitempty"`
+	Namespace        *string              `yaml:"namespace,omitempty"`
+	PullSecret       *string              `yaml:"pullSecret,omitempty"`
+	Image            *string              `yaml:"image,omitempty"`
+	ServiceAccount   *string              `yaml:"serviceAccount,omitempty"`
+	Resources        *ResourceConfig      `yaml:"resources,omitempty"`
+	NodeSelector     *map[string]*string  `yaml:"nodeSelector,omitempty"`
+	Tolerations      *[]*TolerationConfig `yaml:"tolerations,omitempty"`
+	Flags            *[]*string           `yaml:"flags,omitempty"`
+	StartTimeout     *int                 `yaml:"startTimeout,omitempty"`
+	BuildTimeout     *int                 `yaml:"buildTimeout,omitempty"`
+	MaxContextSize   *string              `yaml:"maxContextSize,omitempty"`
+}
+
+// ResourceConfig defines the resource requests and limits of a container (e.g. cpu: 500m, memory: 1Gi)
+type ResourceConfig struct {
+	Requests *map[string]*string `yaml:"requests,omitempty"`
+	Limits   *map[string]*string `yaml:"limits,omitempty"`
+}
+
+// TolerationConfig defines a toleration that allows a pod to be scheduled on tainted nodes
+type TolerationConfig struct {
+	Key               *string `yaml:"key,omitempty"`
+	Operator          *string `yaml:"operator,omitempty"`
+	Value             *string `yaml:"value,omitempty"`
+	Effect            *string `yaml:"effect,omitempty"`
+	TolerationSeconds *int64  `yaml:"tolerationSeconds,omitempty"`
 }
 
 // DockerConfig tells the DevSpace CLI to build with Docker on Minikube or on localhost
@@ -33,9 +78,25 @@ type DockerConfig struct {
 	PreferMinikube *bool `yaml:"preferMinikube,omitempty"`
 }
 
+// CustomConfig tells the DevSpace CLI to build with a custom command (e.g. buildah, img, jib or bazel)
+type CustomConfig struct {
+	Command *string    `yaml:"command"`
+	Args    *[]*string `yaml:"args,omitempty"`
+}
+
 //BuildOptions defines options for building Docker images
 type BuildOptions struct {
-	BuildArgs *map[string]*string `yaml:"buildArgs,omitempty"`
-	Target    *string             `yaml:"target,omitempty"`
-	Network   *string             `yaml:"network,omitempty"`
+	BuildArgs *map[string]*string   `yaml:"buildArgs,omitempty"`
+	Target    *string               `yaml:"target,omitempty"`
+	Network   *string               `yaml:"network,omitempty"`
+	Secrets   *[]*BuildSecretConfig `yaml:"secrets,omitempty"`
+	SSH       *bool                 `yaml:"ssh,omitempty"`
+}
+
+// BuildSecretConfig defines a secret that is available during the build without being stored in the image, the value is
+// read from a local file or an environment variable
+type BuildSecretConfig struct {
+	ID   *string `yaml:"id"`
+	File *string `yaml:"file,omitempty"`
+	Env  *string `yaml:"env,omitempty"`
 }
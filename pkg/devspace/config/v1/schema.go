@@ -12,6 +12,7 @@ type Config struct {
 	Cluster          *Cluster                    `yaml:"cluster,omitempty"`
 	Tiller           *TillerConfig               `yaml:"tiller,omitempty"`
 	InternalRegistry *InternalRegistryConfig     `yaml:"internalRegistry,omitempty"`
+	Build            *BuildSettings              `yaml:"build,omitempty"`
 }
 
 // TillerConfig defines the tiller service
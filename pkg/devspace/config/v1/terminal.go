@@ -9,4 +9,8 @@ type Terminal struct {
 	Namespace     *string             `yaml:"namespace"`
 	ContainerName *string             `yaml:"containerName"`
 	Command       *[]*string          `yaml:"command"`
+	WorkDir       *string             `yaml:"workDir,omitempty"`
+	Env           *map[string]*string `yaml:"env,omitempty"`
+	User          *string             `yaml:"user,omitempty"`
+	Reconnect     *bool               `yaml:"reconnect,omitempty"`
 }
@@ -9,10 +9,12 @@ import (
 	"github.com/covexo/devspace/pkg/devspace/config/generated"
 	"github.com/covexo/devspace/pkg/devspace/config/v1"
 	"github.com/covexo/devspace/pkg/devspace/helm"
+	"github.com/covexo/devspace/pkg/devspace/image"
 	"github.com/covexo/devspace/pkg/devspace/registry"
 	"github.com/covexo/devspace/pkg/util/hash"
 	"github.com/covexo/devspace/pkg/util/log"
 	"github.com/covexo/devspace/pkg/util/yamlutil"
+	k8sv1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes"
 )
 
@@ -250,6 +252,11 @@ func (d *DeployConfig) internalDeploy(generatedConfig *generated.Config, helmCli
 		}
 		container["image"] = registry.GetImageURL(generatedConfig, imageConf, true)
 
+		// Side-loaded images cannot be pulled from a registry
+		if image.IsSideLoaded(imageConf) {
+			container["imagePullPolicy"] = string(k8sv1.PullIfNotPresent)
+		}
+
 		overwriteContainerValues[imageName] = container
 	}
 
@@ -11,7 +11,11 @@ import (
 	"github.com/covexo/devspace/pkg/devspace/config/generated"
 
 	"github.com/covexo/devspace/pkg/devspace/config/v1"
+	"github.com/covexo/devspace/pkg/devspace/image"
+	"github.com/covexo/devspace/pkg/devspace/registry"
 	"github.com/covexo/devspace/pkg/util/log"
+	"github.com/docker/distribution/reference"
+	k8sv1 "k8s.io/api/core/v1"
 )
 
 // DeployConfig holds the necessary information for kubectl deployment
@@ -109,7 +113,9 @@ func (d *DeployConfig) Deploy(generatedConfig *generated.Config, forceDeploy boo
 		return err
 	}
 
+	sideLoadedImages := getSideLoadedImages()
 	for _, manifest := range manifests {
+		setImagePullPolicy(map[interface{}]interface{}(manifest), sideLoadedImages)
 		replaceManifest(manifest, generatedConfig.ImageTags)
 	}
 
@@ -170,3 +176,48 @@ func replaceManifest(manifest Manifest, tags map[string]string) {
 
 	Walk(map[interface{}]interface{}(manifest), match, replace)
 }
+
+// getSideLoadedImages returns the names of the images that are loaded into the cluster nodes instead of being pushed
+func getSideLoadedImages() map[string]bool {
+	config := configutil.GetConfig()
+	sideLoadedImages := map[string]bool{}
+
+	if config.Images != nil {
+		for _, imageConf := range *config.Images {
+			if image.IsSideLoaded(imageConf) {
+				sideLoadedImages[getRepository(registry.GetImageURL(nil, imageConf, false))] = true
+			}
+		}
+	}
+
+	return sideLoadedImages
+}
+
+// setImagePullPolicy sets the imagePullPolicy of containers that use one of the image repositories (with any tag) to
+// IfNotPresent, because side-loaded images cannot be pulled from a registry
+func setImagePullPolicy(d interface{}, images map[string]bool) {
+	switch t := d.(type) {
+	case []interface{}:
+		for _, val := range t {
+			setImagePullPolicy(val, images)
+		}
+	case map[interface{}]interface{}:
+		if containerImage, ok := t["image"].(string); ok && images[getRepository(containerImage)] {
+			t["imagePullPolicy"] = string(k8sv1.PullIfNotPresent)
+		}
+
+		for _, v := range t {
+			setImagePullPolicy(v, images)
+		}
+	}
+}
+
+// getRepository returns the normalized repository of an image without tag or digest, e.g. docker.io/library/nginx for nginx:latest
+func getRepository(image string) string {
+	ref, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return image
+	}
+
+	return ref.Name()
+}
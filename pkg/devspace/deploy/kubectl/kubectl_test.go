@@ -0,0 +1,62 @@
+package kubectl
+
+import (
+	"testing"
+)
+
+func TestSetImagePullPolicy(t *testing.T) {
+	sideLoadedImages := map[string]bool{
+		getRepository("user/app"): true,
+	}
+
+	testCases := []struct {
+		name           string
+		image          string
+		expectedPolicy interface{}
+	}{
+		{
+			name:           "untagged image",
+			image:          "user/app",
+			expectedPolicy: "IfNotPresent",
+		},
+		{
+			name:           "latest tag",
+			image:          "user/app:latest",
+			expectedPolicy: "IfNotPresent",
+		},
+		{
+			name:           "normalized name",
+			image:          "docker.io/user/app:v1",
+			expectedPolicy: "IfNotPresent",
+		},
+		{
+			name:           "other image",
+			image:          "user/other:latest",
+			expectedPolicy: "Always",
+		},
+		{
+			name:           "image with the same prefix",
+			image:          "user/app-db",
+			expectedPolicy: "Always",
+		},
+	}
+
+	for _, testCase := range testCases {
+		container := map[interface{}]interface{}{
+			"name":            "container",
+			"image":           testCase.image,
+			"imagePullPolicy": "Always",
+		}
+		manifest := map[interface{}]interface{}{
+			"spec": map[interface{}]interface{}{
+				"containers": []interface{}{container},
+			},
+		}
+
+		setImagePullPolicy(manifest, sideLoadedImages)
+
+		if container["imagePullPolicy"] != testCase.expectedPolicy {
+			t.Fatalf("Test case %s: expected imagePullPolicy %v, got %v", testCase.name, testCase.expectedPolicy, container["imagePullPolicy"])
+		}
+	}
+}
@@ -87,6 +87,18 @@ func newDockerClientFromMinikube() (client.CommonAPIClient, error) {
 	return client.NewClient(host, version, httpclient, nil)
 }
 
+// GetClientEnvironment returns the environment variables a docker cli needs to use the same docker daemon as NewClient
+func GetClientEnvironment(preferMinikube bool) map[string]string {
+	if preferMinikube && kubectl.IsMinikube() {
+		env, err := getMinikubeEnvironment()
+		if err == nil {
+			return env
+		}
+	}
+
+	return map[string]string{}
+}
+
 func getMinikubeEnvironment() (map[string]string, error) {
 	cmd := exec.Command("minikube", "docker-env", "--shell", "none")
 	out, err := cmd.Output()
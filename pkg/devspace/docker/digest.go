@@ -0,0 +1,50 @@
+package docker
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/docker/distribution/reference"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/registry"
+)
+
+// GetImageDigest asks the registry of the image for the digest the image currently points to. The credentials are taken from
+// the docker credentials store
+func GetImageDigest(client client.CommonAPIClient, image string) (string, error) {
+	ref, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return "", err
+	}
+
+	repoInfo, err := registry.ParseRepositoryInfo(ref)
+	if err != nil {
+		return "", err
+	}
+
+	registryURL := ""
+	if repoInfo.Index.Official == false {
+		registryURL = repoInfo.Index.Name
+	}
+
+	encodedAuth := ""
+
+	// Public images can be inspected without credentials
+	authConfig, err := GetAuthConfig(client, registryURL, true)
+	if err == nil {
+		buf, err := json.Marshal(authConfig)
+		if err != nil {
+			return "", err
+		}
+
+		encodedAuth = base64.URLEncoding.EncodeToString(buf)
+	}
+
+	inspect, err := client.DistributionInspect(context.Background(), reference.FamiliarString(reference.TagNameOnly(ref)), encodedAuth)
+	if err != nil {
+		return "", err
+	}
+
+	return inspect.Descriptor.Digest.String(), nil
+}
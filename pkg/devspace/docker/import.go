@@ -0,0 +1,86 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// ImportImageIntoNodes imports an image of the docker daemon into the containerd of every node container with the
+// given label (e.g. the nodes of a kind or k3d cluster), like docker save piped into ctr images import
+func ImportImageIntoNodes(client client.CommonAPIClient, image, nodeLabel string, out io.Writer) error {
+	ctx := context.Background()
+
+	nodes, err := client.ContainerList(ctx, types.ContainerListOptions{
+		Filters: filters.NewArgs(filters.Arg("label", nodeLabel)),
+	})
+	if err != nil {
+		return fmt.Errorf("Error listing cluster nodes: %v", err)
+	}
+	if len(nodes) == 0 {
+		return fmt.Errorf("No running node container with label %s found", nodeLabel)
+	}
+
+	for _, node := range nodes {
+		err = importImageIntoNode(ctx, client, image, node.ID, out)
+		if err != nil {
+			return fmt.Errorf("Error importing image into node %s: %v", node.Names, err)
+		}
+	}
+
+	return nil
+}
+
+func importImageIntoNode(ctx context.Context, client client.CommonAPIClient, image, containerID string, out io.Writer) error {
+	imageReader, err := client.ImageSave(ctx, []string{image})
+	if err != nil {
+		return err
+	}
+	defer imageReader.Close()
+
+	execID, err := client.ContainerExecCreate(ctx, containerID, types.ExecConfig{
+		AttachStdin:  true,
+		AttachStdout: true,
+		AttachStderr: true,
+		Cmd:          []string{"ctr", "--namespace=k8s.io", "images", "import", "-"},
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.ContainerExecAttach(ctx, execID.ID, types.ExecStartCheck{})
+	if err != nil {
+		return err
+	}
+	defer resp.Close()
+
+	uploadErr := make(chan error, 1)
+	go func() {
+		_, err := io.Copy(resp.Conn, imageReader)
+		resp.CloseWrite()
+		uploadErr <- err
+	}()
+
+	_, err = stdcopy.StdCopy(out, out, resp.Reader)
+	if err != nil {
+		return err
+	}
+	if err := <-uploadErr; err != nil {
+		return err
+	}
+
+	inspect, err := client.ContainerExecInspect(ctx, execID.ID)
+	if err != nil {
+		return err
+	}
+	if inspect.ExitCode != 0 {
+		return fmt.Errorf("ctr images import exited with exit code %d", inspect.ExitCode)
+	}
+
+	return nil
+}
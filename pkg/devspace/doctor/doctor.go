@@ -0,0 +1,358 @@
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/covexo/devspace/pkg/devspace/config/configutil"
+	"github.com/covexo/devspace/pkg/devspace/docker"
+	helmClient "github.com/covexo/devspace/pkg/devspace/helm"
+	"github.com/covexo/devspace/pkg/devspace/kubectl"
+	"github.com/covexo/devspace/pkg/util/kubeconfig"
+	"github.com/covexo/devspace/pkg/util/log"
+	dockerregistry "github.com/docker/docker/registry"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Status of a check
+const (
+	StatusPassed  = "Passed"
+	StatusWarning = "Warning"
+	StatusFailed  = "Failed"
+	StatusSkipped = "Skipped"
+)
+
+// Result is the result of a single check with a hint how to fix it
+type Result struct {
+	Check  string
+	Status string
+	Info   string
+	Hint   string
+}
+
+// Run executes all checks. Checks that depend on a valid config or a reachable cluster are skipped if these checks fail
+func Run(log log.Logger) []*Result {
+	results := []*Result{}
+
+	configResult := checkConfig()
+	results = append(results, configResult)
+	if configResult.Status == StatusFailed {
+		return append(results, skipped("Kube context", "Config is invalid"), skipped("Docker", "Config is invalid"))
+	}
+
+	log.StartWait("Checking cluster")
+	client, serverVersion, contextResult := checkKubeContext()
+	log.StopWait()
+
+	results = append(results, contextResult)
+
+	if client != nil {
+		log.StartWait("Checking permissions")
+		results = append(results, checkPermissions(client)...)
+		log.StopWait()
+
+		results = append(results, checkTiller(client))
+	} else {
+		results = append(results, skipped("Permissions", "Cluster is not reachable"), skipped("Tiller", "Cluster is not reachable"))
+	}
+
+	log.StartWait("Checking docker")
+	results = append(results, checkDocker())
+	log.StopWait()
+
+	log.StartWait("Checking minikube")
+	results = append(results, checkMinikube())
+	log.StopWait()
+
+	results = append(results, checkRegistryCredentials()...)
+
+	log.StartWait("Checking tool versions")
+	results = append(results, checkToolVersions(serverVersion)...)
+	log.StopWait()
+
+	return results
+}
+
+// HasFailed returns true if at least one check failed
+func HasFailed(results []*Result) bool {
+	for _, result := range results {
+		if result.Status == StatusFailed {
+			return true
+		}
+	}
+
+	return false
+}
+
+func skipped(check, reason string) *Result {
+	return &Result{
+		Check:  check,
+		Status: StatusSkipped,
+		Info:   reason,
+	}
+}
+
+func checkConfig() *Result {
+	configExists, err := configutil.ConfigExists()
+	if err != nil || configExists == false {
+		return &Result{
+			Check:  "Config",
+			Status: StatusFailed,
+			Info:   fmt.Sprintf("Config %s not found", configutil.ConfigPath),
+			Hint:   "Run `devspace init` to create a config",
+		}
+	}
+
+	err = configutil.CheckConfig()
+	if err != nil {
+		return &Result{
+			Check:  "Config",
+			Status: StatusFailed,
+			Info:   err.Error(),
+			Hint:   "Fix the config or run `devspace init -r` to recreate it",
+		}
+	}
+
+	return &Result{
+		Check:  "Config",
+		Status: StatusPassed,
+		Info:   fmt.Sprintf("%s is valid", configutil.ConfigPath),
+	}
+}
+
+// checkKubeContext returns the client and the server version if the cluster is reachable
+func checkKubeContext() (*kubernetes.Clientset, *version.Info, *Result) {
+	config := configutil.GetConfig()
+
+	contextName := ""
+	if config.Cluster != nil && config.Cluster.APIServer != nil {
+		contextName = *config.Cluster.APIServer
+	} else if config.Cluster != nil && config.Cluster.KubeContext != nil {
+		contextName = *config.Cluster.KubeContext
+	} else {
+		contextName, _ = kubeconfig.GetCurrentContext()
+	}
+
+	client, err := kubectl.NewClient()
+	if err != nil {
+		return nil, nil, &Result{
+			Check:  "Kube context",
+			Status: StatusFailed,
+			Info:   fmt.Sprintf("Cannot create client for context %s: %v", contextName, err),
+			Hint:   "Check your kube config with `kubectl config view` or the cluster section of the config",
+		}
+	}
+
+	serverVersion, err := client.Discovery().ServerVersion()
+	if err != nil {
+		return nil, nil, &Result{
+			Check:  "Kube context",
+			Status: StatusFailed,
+			Info:   fmt.Sprintf("Cluster of context %s is not reachable: %v", contextName, err),
+			Hint:   "Check that the api server is reachable and your credentials are valid (e.g. with `kubectl get pods`)",
+		}
+	}
+
+	return client, serverVersion, &Result{
+		Check:  "Kube context",
+		Status: StatusPassed,
+		Info:   fmt.Sprintf("Context %s (Kubernetes %s) is reachable", contextName, serverVersion.GitVersion),
+	}
+}
+
+func checkTiller(client *kubernetes.Clientset) *Result {
+	config := configutil.GetConfig()
+	if config.Tiller == nil || config.Tiller.Namespace == nil {
+		return skipped("Tiller", "No tiller configured")
+	}
+
+	tillerNamespace := *config.Tiller.Namespace
+	deployment, err := client.ExtensionsV1beta1().Deployments(tillerNamespace).Get(helmClient.TillerDeploymentName, metav1.GetOptions{})
+	if err != nil {
+		return &Result{
+			Check:  "Tiller",
+			Status: StatusWarning,
+			Info:   fmt.Sprintf("Tiller not found in namespace %s: %v", tillerNamespace, err),
+			Hint:   "Tiller is installed automatically by `devspace up` or `devspace deploy`",
+		}
+	}
+
+	if deployment.Status.ReadyReplicas == 0 || deployment.Status.ReadyReplicas != deployment.Status.Replicas {
+		info := fmt.Sprintf("Tiller in namespace %s is not ready (%d/%d replicas ready)", tillerNamespace, deployment.Status.ReadyReplicas, deployment.Status.Replicas)
+
+		pods, err := kubectl.GetPodsFromDeployment(client, helmClient.TillerDeploymentName, tillerNamespace)
+		if err == nil {
+			for _, pod := range pods.Items {
+				if status := kubectl.GetPodStatus(&pod); status != "Running" {
+					info += fmt.Sprintf(", pod %s: %s", pod.Name, status)
+				}
+			}
+		}
+
+		return &Result{
+			Check:  "Tiller",
+			Status: StatusFailed,
+			Info:   info,
+			Hint:   fmt.Sprintf("Check the tiller pods with `kubectl describe pods -n %s -l app=helm` or reinstall tiller with `devspace reset`", tillerNamespace),
+		}
+	}
+
+	image := ""
+	if len(deployment.Spec.Template.Spec.Containers) > 0 {
+		image = deployment.Spec.Template.Spec.Containers[0].Image
+	}
+
+	return &Result{
+		Check:  "Tiller",
+		Status: StatusPassed,
+		Info:   fmt.Sprintf("Tiller in namespace %s is ready (%s)", tillerNamespace, image),
+	}
+}
+
+func checkDocker() *Result {
+	config := configutil.GetConfig()
+
+	// Docker is required if at least one image is built with docker or pushed with docker after a custom build
+	required := false
+	preferMinikube := true
+	if config.Images != nil {
+		for _, imageConf := range *config.Images {
+			if imageConf.Build != nil && imageConf.Build.Disabled != nil && *imageConf.Build.Disabled == true {
+				continue
+			}
+			if imageConf.Build != nil && imageConf.Build.Custom != nil {
+				if imageConf.SkipPush == nil || *imageConf.SkipPush == false {
+					required = true
+				}
+
+				continue
+			}
+			if imageConf.Build == nil || imageConf.Build.Kaniko == nil {
+				required = true
+
+				if imageConf.Build != nil && imageConf.Build.Docker != nil && imageConf.Build.Docker.PreferMinikube != nil {
+					preferMinikube = *imageConf.Build.Docker.PreferMinikube
+				}
+			}
+		}
+	}
+
+	failedStatus := StatusWarning
+	if required {
+		failedStatus = StatusFailed
+	}
+
+	client, err := docker.NewClient(preferMinikube)
+	if err != nil {
+		return &Result{
+			Check:  "Docker",
+			Status: failedStatus,
+			Info:   fmt.Sprintf("Cannot create docker client: %v", err),
+			Hint:   "Install docker or build your images with kaniko (images.*.build.kaniko)",
+		}
+	}
+
+	serverVersion, err := client.ServerVersion(context.Background())
+	if err != nil {
+		return &Result{
+			Check:  "Docker",
+			Status: failedStatus,
+			Info:   fmt.Sprintf("Docker daemon is not reachable: %v", err),
+			Hint:   "Start the docker daemon (check `docker info`) or build your images with kaniko (images.*.build.kaniko)",
+		}
+	}
+
+	return &Result{
+		Check:  "Docker",
+		Status: StatusPassed,
+		Info:   fmt.Sprintf("Docker daemon %s is reachable", serverVersion.Version),
+	}
+}
+
+func checkMinikube() *Result {
+	if kubectl.IsMinikube() == false {
+		return skipped("Minikube", "Current context is not minikube")
+	}
+
+	out, err := exec.Command("minikube", "status").CombinedOutput()
+	if err != nil {
+		return &Result{
+			Check:  "Minikube",
+			Status: StatusFailed,
+			Info:   fmt.Sprintf("Minikube is not running: %s", strings.TrimSpace(string(out))),
+			Hint:   "Start minikube with `minikube start`",
+		}
+	}
+
+	return &Result{
+		Check:  "Minikube",
+		Status: StatusPassed,
+		Info:   "Minikube is running",
+	}
+}
+
+func checkRegistryCredentials() []*Result {
+	config := configutil.GetConfig()
+	if config.Registries == nil || len(*config.Registries) == 0 {
+		return []*Result{skipped("Registry credentials", "No registries configured")}
+	}
+
+	authConfigs, _ := docker.GetAllAuthConfigs()
+	authHostnames := map[string]bool{}
+	for serverAddress, authConfig := range authConfigs {
+		if authConfig.Username != "" || authConfig.IdentityToken != "" || authConfig.RegistryToken != "" {
+			authHostnames[getRegistryHostname(serverAddress)] = true
+		}
+	}
+
+	results := []*Result{}
+	for name, registryConf := range *config.Registries {
+		check := "Registry " + name
+		registryURL := ""
+		if registryConf.URL != nil {
+			registryURL = *registryConf.URL
+		}
+
+		if registryConf.Auth != nil && registryConf.Auth.Username != nil && *registryConf.Auth.Username != "" {
+			results = append(results, &Result{
+				Check:  check,
+				Status: StatusPassed,
+				Info:   fmt.Sprintf("Credentials for %s are defined in the config", registryURL),
+			})
+			continue
+		}
+
+		if authHostnames[getRegistryHostname(registryURL)] {
+			results = append(results, &Result{
+				Check:  check,
+				Status: StatusPassed,
+				Info:   fmt.Sprintf("Found docker credentials for %s", registryURL),
+			})
+			continue
+		}
+
+		results = append(results, &Result{
+			Check:  check,
+			Status: StatusWarning,
+			Info:   fmt.Sprintf("No credentials for %s found, pushing images will fail if the registry requires authentication", registryURL),
+			Hint:   fmt.Sprintf("Run `docker login %s` or define registries.%s.auth in the config", registryURL, name),
+		})
+	}
+
+	return results
+}
+
+// getRegistryHostname returns the hostname of a registry url, docker hub urls are normalized to index.docker.io
+func getRegistryHostname(registryURL string) string {
+	hostname := dockerregistry.ConvertToHostname(registryURL)
+	switch hostname {
+	case "", "hub.docker.com", "docker.io", "registry-1.docker.io":
+		return "index.docker.io"
+	}
+
+	return hostname
+}
@@ -0,0 +1,163 @@
+package doctor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/covexo/devspace/pkg/devspace/config/configutil"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// permission defines the verbs devspace needs for a resource
+type permission struct {
+	Group       string
+	Resource    string
+	Subresource string
+	Verbs       []string
+
+	// ClusterScoped resources are checked without namespace
+	ClusterScoped bool
+
+	// Tiller permissions are checked in the tiller namespace
+	Tiller bool
+
+	// Missing optional permissions only disable single features and are reported as warnings
+	Optional bool
+
+	Usage string
+}
+
+// permissions are all permissions devspace needs for the resources it touches
+var permissions = []permission{
+	{Resource: "namespaces", Verbs: []string{"get", "create"}, ClusterScoped: true, Optional: true, Usage: "create the default namespace"},
+	{Resource: "pods", Verbs: []string{"get", "list", "watch", "create", "delete"}, Usage: "select pods, kaniko builds and debug pods"},
+	{Resource: "pods", Subresource: "exec", Verbs: []string{"create"}, Usage: "terminal, exec, sync and cp"},
+	{Resource: "pods", Subresource: "portforward", Verbs: []string{"create"}, Usage: "port forwarding"},
+	{Resource: "pods", Subresource: "attach", Verbs: []string{"create"}, Optional: true, Usage: "attach and debug containers"},
+	{Resource: "pods", Subresource: "log", Verbs: []string{"get"}, Usage: "logs"},
+	{Resource: "events", Verbs: []string{"list"}, Optional: true, Usage: "pod diagnostics"},
+	{Resource: "secrets", Verbs: []string{"get", "create", "update", "delete"}, Usage: "image pull secrets"},
+	{Resource: "services", Verbs: []string{"get", "list"}, Optional: true, Usage: "internal registry"},
+	{Group: "apps", Resource: "deployments", Verbs: []string{"get", "update"}, Optional: true, Usage: "dev mode"},
+	{Group: "apps", Resource: "statefulsets", Verbs: []string{"get", "update"}, Optional: true, Usage: "dev mode"},
+	{Group: "extensions", Resource: "deployments", Verbs: []string{"get", "create", "update"}, Tiller: true, Usage: "install tiller"},
+	{Resource: "serviceaccounts", Verbs: []string{"get", "create"}, Tiller: true, Usage: "install tiller"},
+	{Group: "rbac.authorization.k8s.io", Resource: "rolebindings", Verbs: []string{"get", "create"}, Tiller: true, Usage: "install tiller"},
+	{Group: "rbac.authorization.k8s.io", Resource: "clusterrolebindings", Verbs: []string{"get", "create"}, ClusterScoped: true, Optional: true, Usage: "cluster-admin binding on google cloud"},
+}
+
+// checkPermissions checks the permissions via SelfSubjectAccessReviews. Returns a single result if all permissions are granted
+func checkPermissions(client *kubernetes.Clientset) []*Result {
+	config := configutil.GetConfig()
+
+	defaultNamespace, err := configutil.GetDefaultNamespace(config)
+	if err != nil {
+		return []*Result{
+			{
+				Check:  "Permissions",
+				Status: StatusFailed,
+				Info:   fmt.Sprintf("Error retrieving default namespace: %v", err),
+			},
+		}
+	}
+
+	tillerNamespace := defaultNamespace
+	if config.Tiller != nil && config.Tiller.Namespace != nil {
+		tillerNamespace = *config.Tiller.Namespace
+	}
+
+	results := []*Result{}
+	checked := 0
+
+	for _, permission := range permissions {
+		namespace := defaultNamespace
+		if permission.Tiller {
+			if config.Tiller == nil {
+				continue
+			}
+
+			namespace = tillerNamespace
+		}
+		if permission.ClusterScoped {
+			namespace = ""
+		}
+
+		missingVerbs := []string{}
+		for _, verb := range permission.Verbs {
+			allowed, err := isAllowed(client, namespace, verb, permission)
+			if err != nil {
+				return append(results, &Result{
+					Check:  "Permissions",
+					Status: StatusWarning,
+					Info:   fmt.Sprintf("Cannot check permissions: %v", err),
+					Hint:   "The cluster has to support the authorization.k8s.io/v1 api",
+				})
+			}
+			if allowed == false {
+				missingVerbs = append(missingVerbs, verb)
+			}
+		}
+
+		checked++
+		if len(missingVerbs) == 0 {
+			continue
+		}
+
+		resource := permission.Resource
+		if permission.Subresource != "" {
+			resource += "/" + permission.Subresource
+		}
+		if permission.Group != "" {
+			resource += "." + permission.Group
+		}
+
+		status := StatusFailed
+		if permission.Optional {
+			status = StatusWarning
+		}
+
+		location := "cluster-wide"
+		hint := "Ask your cluster admin for a ClusterRoleBinding with these permissions"
+		if namespace != "" {
+			location = "in namespace " + namespace
+			hint = fmt.Sprintf("Ask your cluster admin for these permissions in namespace %s (e.g. a RoleBinding to the ClusterRole admin)", namespace)
+		}
+
+		results = append(results, &Result{
+			Check:  "Permission " + resource,
+			Status: status,
+			Info:   fmt.Sprintf("Missing %s %s (needed for %s)", strings.Join(missingVerbs, ", "), location, permission.Usage),
+			Hint:   hint,
+		})
+	}
+
+	if len(results) == 0 {
+		results = append(results, &Result{
+			Check:  "Permissions",
+			Status: StatusPassed,
+			Info:   fmt.Sprintf("All %d required permissions are granted", checked),
+		})
+	}
+
+	return results
+}
+
+func isAllowed(client *kubernetes.Clientset, namespace, verb string, permission permission) (bool, error) {
+	review, err := client.AuthorizationV1().SelfSubjectAccessReviews().Create(&authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace:   namespace,
+				Verb:        verb,
+				Group:       permission.Group,
+				Resource:    permission.Resource,
+				Subresource: permission.Subresource,
+			},
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return review.Status.Allowed, nil
+}
@@ -0,0 +1,125 @@
+package doctor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/covexo/devspace/pkg/devspace/config/configutil"
+	"github.com/covexo/devspace/pkg/devspace/upgrade"
+	"k8s.io/apimachinery/pkg/version"
+)
+
+// maxKubectlVersionSkew is the number of minor versions kubectl may differ from the api server
+const maxKubectlVersionSkew = 1
+
+// checkToolVersions checks the devspace version and the version of kubectl, which is required by the kubectl deployment method
+func checkToolVersions(serverVersion *version.Info) []*Result {
+	config := configutil.GetConfig()
+
+	devspaceVersion := upgrade.GetVersion()
+	if devspaceVersion == "" {
+		devspaceVersion = "unknown (development build)"
+	}
+
+	results := []*Result{
+		{
+			Check:  "DevSpace CLI",
+			Status: StatusPassed,
+			Info:   "Version " + devspaceVersion,
+		},
+	}
+
+	// kubectl is only required for deployments with the kubectl method
+	cmdPath := ""
+	if config.DevSpace != nil && config.DevSpace.Deployments != nil {
+		for _, deployConfig := range *config.DevSpace.Deployments {
+			if deployConfig.Kubectl != nil {
+				cmdPath = "kubectl"
+				if deployConfig.Kubectl.CmdPath != nil {
+					cmdPath = *deployConfig.Kubectl.CmdPath
+				}
+			}
+		}
+	}
+
+	if cmdPath == "" {
+		return append(results, skipped("kubectl", "No deployment uses kubectl"))
+	}
+
+	clientVersion, err := getKubectlVersion(cmdPath)
+	if err != nil {
+		return append(results, &Result{
+			Check:  "kubectl",
+			Status: StatusFailed,
+			Info:   fmt.Sprintf("Cannot run %s: %v", cmdPath, err),
+			Hint:   "Install kubectl (https://kubernetes.io/docs/tasks/tools/install-kubectl/) or set deployments.*.kubectl.cmdPath",
+		})
+	}
+
+	if serverVersion != nil {
+		skew, err := getMinorVersionSkew(clientVersion, serverVersion)
+		if err == nil && skew > maxKubectlVersionSkew {
+			return append(results, &Result{
+				Check:  "kubectl",
+				Status: StatusWarning,
+				Info:   fmt.Sprintf("kubectl %s differs more than %d minor version from the cluster (%s)", clientVersion.GitVersion, maxKubectlVersionSkew, serverVersion.GitVersion),
+				Hint:   "Install a kubectl version that matches your cluster",
+			})
+		}
+	}
+
+	return append(results, &Result{
+		Check:  "kubectl",
+		Status: StatusPassed,
+		Info:   "Version " + clientVersion.GitVersion,
+	})
+}
+
+func getKubectlVersion(cmdPath string) (*version.Info, error) {
+	out, err := exec.Command(cmdPath, "version", "--client", "-o", "json").Output()
+	if err != nil {
+		return nil, err
+	}
+
+	kubectlVersion := &struct {
+		ClientVersion *version.Info `json:"clientVersion"`
+	}{}
+
+	err = json.Unmarshal(out, kubectlVersion)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing kubectl version: %v", err)
+	}
+	if kubectlVersion.ClientVersion == nil {
+		return nil, errors.New("kubectl returned no client version")
+	}
+
+	return kubectlVersion.ClientVersion, nil
+}
+
+func getMinorVersionSkew(clientVersion, serverVersion *version.Info) (int, error) {
+	if clientVersion.Major != serverVersion.Major {
+		return 0, errors.New("Major versions differ")
+	}
+
+	// Some providers add a + to the minor version (e.g. 11+)
+	clientMinor, err := strconv.Atoi(strings.TrimSuffix(clientVersion.Minor, "+"))
+	if err != nil {
+		return 0, err
+	}
+
+	serverMinor, err := strconv.Atoi(strings.TrimSuffix(serverVersion.Minor, "+"))
+	if err != nil {
+		return 0, err
+	}
+
+	skew := clientMinor - serverMinor
+	if skew < 0 {
+		skew = -skew
+	}
+
+	return skew, nil
+}
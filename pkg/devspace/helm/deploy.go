@@ -15,6 +15,8 @@ import (
 
 // WaitForReleasePodToGetReady waits for the release pod to get ready
 func WaitForReleasePodToGetReady(client *kubernetes.Clientset, releaseName, releaseNamespace string, releaseRevision int) (*k8sv1.Pod, error) {
+	reporter := kubectl.NewPodEventReporter(client, log.GetInstance())
+
 	for true {
 		time.Sleep(4 * time.Second)
 
@@ -26,6 +28,13 @@ func WaitForReleasePodToGetReady(client *kubernetes.Clientset, releaseName, rele
 			log.Panicf("Unable to list devspace pods: %s", err.Error())
 		}
 
+		pods := make([]*k8sv1.Pod, 0, len(podList.Items))
+		for index := range podList.Items {
+			pods = append(pods, &podList.Items[index])
+		}
+
+		reporter.Report(pods)
+
 		if len(podList.Items) > 0 {
 			highestRevision := 0
 			var selectedPod *k8sv1.Pod
@@ -60,7 +69,7 @@ func WaitForReleasePodToGetReady(client *kubernetes.Clientset, releaseName, rele
 						log.Warn("Found pod without revision. Use annotation 'revision' for your pods to avoid this warning.")
 					}
 
-					err = waitForPodReady(client, selectedPod, 2*60*time.Second, 5*time.Second)
+					err = waitForPodReady(client, selectedPod, 2*60*time.Second, 5*time.Second, reporter)
 					if err != nil {
 						return nil, fmt.Errorf("Error during waiting for pod: %s", err.Error())
 					}
@@ -78,21 +87,25 @@ func WaitForReleasePodToGetReady(client *kubernetes.Clientset, releaseName, rele
 	return nil, nil
 }
 
-func waitForPodReady(kubectl *kubernetes.Clientset, pod *k8sv1.Pod, maxWaitTime time.Duration, checkInterval time.Duration) error {
+func waitForPodReady(client *kubernetes.Clientset, pod *k8sv1.Pod, maxWaitTime time.Duration, checkInterval time.Duration, reporter *kubectl.PodEventReporter) error {
 	for maxWaitTime > 0 {
-		pod, err := kubectl.Core().Pods(pod.Namespace).Get(pod.Name, metav1.GetOptions{})
+		currentPod, err := client.Core().Pods(pod.Namespace).Get(pod.Name, metav1.GetOptions{})
 
 		if err != nil {
 			return err
 		}
 
-		if len(pod.Status.ContainerStatuses) > 0 && pod.Status.ContainerStatuses[0].Ready {
+		if len(currentPod.Status.ContainerStatuses) > 0 && currentPod.Status.ContainerStatuses[0].Ready {
 			return nil
 		}
 
+		reporter.Report([]*k8sv1.Pod{currentPod})
+		pod = currentPod
+
 		time.Sleep(checkInterval)
 		maxWaitTime = maxWaitTime - checkInterval
 	}
 
+	reporter.PrintSummary("release="+pod.Labels["release"], pod.Namespace, []*k8sv1.Pod{pod})
 	return fmt.Errorf("Max wait time expired")
 }
@@ -5,10 +5,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
 
 	"k8s.io/client-go/kubernetes"
 
 	"github.com/covexo/devspace/pkg/devspace/builder"
+	"github.com/covexo/devspace/pkg/devspace/builder/custom"
 	"github.com/covexo/devspace/pkg/devspace/builder/docker"
 	"github.com/covexo/devspace/pkg/devspace/builder/kaniko"
 	"github.com/covexo/devspace/pkg/devspace/config/configutil"
@@ -16,40 +20,222 @@ import (
 	"github.com/covexo/devspace/pkg/devspace/config/v1"
 	dockerclient "github.com/covexo/devspace/pkg/devspace/docker"
 	"github.com/covexo/devspace/pkg/devspace/registry"
-	"github.com/covexo/devspace/pkg/util/hash"
 	"github.com/covexo/devspace/pkg/util/log"
-	"github.com/covexo/devspace/pkg/util/randutil"
-	"github.com/docker/cli/cli/command/image/build"
 	"github.com/docker/docker/api/types"
-	"github.com/docker/docker/pkg/archive"
 )
 
-// BuildAll builds all images
+// Engines images can be built with
+const (
+	engineDocker = "docker"
+	engineKaniko = "kaniko"
+	engineCustom = "custom"
+)
+
+// generatedConfigMutex guards the generated config while images are built in parallel
+var generatedConfigMutex sync.Mutex
+
+// authMutex serializes the registry authentication, because kaniko builders of the same registry share a pull secret
+var authMutex sync.Mutex
+
+type buildResult struct {
+	imageName string
+	rebuilt   bool
+	err       error
+}
+
+// BuildAll builds all images. Images are built after the images they depend on and independent images are built
+// in parallel (config build.parallelism, defaults to the number of cpus)
 func BuildAll(client *kubernetes.Clientset, generatedConfig *generated.Config, forceRebuild bool, log log.Logger) (bool, error) {
 	config := configutil.GetConfig()
-	re := false
 
-	for imageName, imageConf := range *config.Images {
-		if imageConf.Build != nil && imageConf.Build.Disabled != nil && *imageConf.Build.Disabled == true {
+	images := map[string]*v1.ImageConfig{}
+	if config.Images != nil {
+		images = *config.Images
+	}
+
+	dependencies, err := getDependencies(images)
+	if err != nil {
+		return false, err
+	}
+
+	buildOrder, err := getBuildOrder(dependencies)
+	if err != nil {
+		return false, err
+	}
+
+	parallelism := runtime.NumCPU()
+	if config.Build != nil && config.Build.Parallelism != nil && *config.Build.Parallelism > 0 {
+		parallelism = *config.Build.Parallelism
+	}
+
+	buildCount := 0
+	for _, imageConf := range images {
+		if isBuildDisabled(imageConf) == false {
+			buildCount++
+		}
+	}
+
+	// Prefix the output of every image, so that it stays readable if several images are built at once
+	usePrefixLogger := parallelism > 1 && buildCount > 1
+
+	return runBuilds(buildOrder, dependencies, parallelism, func(imageName string, dependencyRebuilt bool) (bool, error) {
+		imageConf := images[imageName]
+		if isBuildDisabled(imageConf) {
 			log.Infof("Skipping building image %s", imageName)
-			continue
+			return false, nil
 		}
 
-		shouldRebuild, err := Build(client, generatedConfig, imageName, imageConf, forceRebuild, log)
-		if err != nil {
-			return false, err
+		dependencyConfs := []*v1.ImageConfig{}
+		for _, dependency := range dependencies[imageName] {
+			dependencyConfs = append(dependencyConfs, images[dependency])
+		}
+
+		imageLog := log
+		if usePrefixLogger {
+			imageLog = newImageLogger(imageName, log)
+		}
+
+		// A new base image has a new tag, so the image has to be rebuilt as well
+		return buildWithDependencies(client, generatedConfig, imageName, imageConf, dependencyConfs, forceRebuild || dependencyRebuilt, imageLog)
+	})
+}
+
+// buildFunc builds an image and returns true if the image was rebuilt. dependencyRebuilt is true if an image it depends on was rebuilt
+type buildFunc func(imageName string, dependencyRebuilt bool) (bool, error)
+
+// runBuilds calls build for the images in build order, with at most parallelism builds at once. An image is started
+// after the images it depends on were built. After an error no more builds are started, but the running builds are
+// awaited and their errors are returned as well. Returns true if an image was rebuilt
+func runBuilds(buildOrder []string, dependencies map[string][]string, parallelism int, build buildFunc) (bool, error) {
+	if parallelism < 1 {
+		parallelism = 1
+	}
+
+	results := map[string]*buildResult{}
+	started := map[string]bool{}
+
+	// Every build sends exactly one result, so no build blocks if the results aren't received anymore
+	resultChan := make(chan *buildResult, len(buildOrder))
+	running := 0
+	completed := 0
+	buildErrors := []string{}
+
+	for completed < len(buildOrder) {
+		for _, imageName := range buildOrder {
+			if len(buildErrors) > 0 || running >= parallelism {
+				break
+			}
+			if started[imageName] {
+				continue
+			}
+
+			dependencyRebuilt := false
+			isReady := true
+
+			for _, dependency := range dependencies[imageName] {
+				dependencyResult, ok := results[dependency]
+				if ok == false {
+					isReady = false
+					break
+				}
+
+				if dependencyResult.rebuilt {
+					dependencyRebuilt = true
+				}
+			}
+			if isReady == false {
+				continue
+			}
+
+			started[imageName] = true
+			running++
+
+			go func(imageName string, dependencyRebuilt bool) {
+				rebuilt, err := build(imageName, dependencyRebuilt)
+				resultChan <- &buildResult{
+					imageName: imageName,
+					rebuilt:   rebuilt,
+					err:       err,
+				}
+			}(imageName, dependencyRebuilt)
+		}
+
+		// Nothing is running and nothing can be started after an error
+		if running == 0 {
+			break
 		}
 
-		if shouldRebuild {
-			re = true
+		result := <-resultChan
+		results[result.imageName] = result
+		running--
+		completed++
+
+		if result.err != nil {
+			buildErrors = append(buildErrors, fmt.Sprintf("Error building image %s: %v", result.imageName, result.err))
+		}
+	}
+
+	if len(buildErrors) > 0 {
+		return false, errors.New(strings.Join(buildErrors, ", "))
+	}
+
+	for _, result := range results {
+		if result.rebuilt {
+			return true, nil
 		}
 	}
 
-	return re, nil
+	return false, nil
+}
+
+// buildWithDependencies builds the image with the current tags of the images it depends on
+func buildWithDependencies(client *kubernetes.Clientset, generatedConfig *generated.Config, imageName string, imageConf *v1.ImageConfig, dependencies []*v1.ImageConfig, forceRebuild bool, log log.Logger) (bool, error) {
+	dependentDockerfilePath, err := createDependentDockerfile(generatedConfig, getDockerfilePath(imageConf), dependencies)
+	if err != nil {
+		return false, fmt.Errorf("Error replacing base images in Dockerfile: %v", err)
+	}
+	if dependentDockerfilePath != "" {
+		defer os.RemoveAll(filepath.Dir(dependentDockerfilePath))
+	}
+
+	return buildImage(client, generatedConfig, imageName, imageConf, dependentDockerfilePath, forceRebuild, log)
+}
+
+// newImageLogger creates a logger that prefixes all messages with the image name
+func newImageLogger(imageName string, parent log.Logger) log.Logger {
+	return log.NewPrefixLogger(imageName, parent)
+}
+
+// openBuildLog creates the build log .devspace/logs/build-<image>.log, which contains the complete output of the last
+// build of the image, and returns a logger that writes to the parent logger and the build log
+func openBuildLog(imageName string, parent log.Logger) (log.Logger, *os.File, error) {
+	err := os.MkdirAll(log.Logdir, os.ModePerm)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	filename := "build-" + strings.NewReplacer("/", "-", "\\", "-", ":", "-").Replace(imageName) + ".log"
+
+	buildLog, err := os.OpenFile(filepath.Join(log.Logdir, filename), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0666)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return log.NewBuildLogger(parent, buildLog), buildLog, nil
+}
+
+func isBuildDisabled(imageConf *v1.ImageConfig) bool {
+	return imageConf.Build != nil && imageConf.Build.Disabled != nil && *imageConf.Build.Disabled == true
 }
 
 // Build builds an image with the specified engine
 func Build(client *kubernetes.Clientset, generatedConfig *generated.Config, imageName string, imageConf *v1.ImageConfig, forceRebuild bool, log log.Logger) (bool, error) {
+	return buildImage(client, generatedConfig, imageName, imageConf, "", forceRebuild, log)
+}
+
+// buildImage builds an image with the specified engine if its build fingerprint changed. If buildDockerfilePath is not empty,
+// the image is built with this Dockerfile instead of the configured one
+func buildImage(client *kubernetes.Clientset, generatedConfig *generated.Config, imageName string, imageConf *v1.ImageConfig, buildDockerfilePath string, forceRebuild bool, log log.Logger) (bool, error) {
 	rebuild := false
 	config := configutil.GetConfig()
 	dockerfilePath := "./Dockerfile"
@@ -65,14 +251,43 @@ func Build(client *kubernetes.Clientset, generatedConfig *generated.Config, imag
 		}
 	}
 
-	if needRebuild, err := shouldRebuild(generatedConfig, imageConf, contextPath, dockerfilePath, forceRebuild); needRebuild || err != nil {
+	if buildDockerfilePath == "" {
+		buildDockerfilePath = dockerfilePath
+	}
+
+	sideLoadTarget, err := getSideLoadTarget(imageConf)
+	if err != nil {
+		return false, err
+	}
+
+	fingerprint, contextHash, err := getBuildFingerprint(imageConf, sideLoadTarget, contextPath, dockerfilePath, buildDockerfilePath, log)
+	if err != nil {
+		return false, fmt.Errorf("Error calculating build fingerprint: %v", err)
+	}
+
+	imageRepository, err := getImageRepository(imageConf)
+	if err != nil {
+		return false, err
+	}
+
+	generatedConfigMutex.Lock()
+	needRebuild := forceRebuild || generatedConfig.ImageFingerprints[imageRepository] != fingerprint
+	generatedConfigMutex.Unlock()
+
+	if needRebuild {
+		dockerfilePath = buildDockerfilePath
+
+		buildLogger, buildLog, err := openBuildLog(imageName, log)
 		if err != nil {
-			return false, fmt.Errorf("Error during shouldRebuild check: %v", err)
+			log.Warnf("Unable to open build log: %v", err)
+		} else {
+			defer buildLog.Close()
+			log = buildLogger
 		}
 
 		absoluteDockerfilePath, err := filepath.Abs(dockerfilePath)
 		if err != nil {
-			return false, fmt.Errorf("Couldn't determine absolute path for %s", *imageConf.Build.DockerfilePath)
+			return false, fmt.Errorf("Couldn't determine absolute path for %s", dockerfilePath)
 		}
 
 		contextPath, err = filepath.Abs(contextPath)
@@ -83,30 +298,40 @@ func Build(client *kubernetes.Clientset, generatedConfig *generated.Config, imag
 		var imageBuilder builder.Interface
 		rebuild = true
 
-		imageTag, err := randutil.GenerateRandomString(7)
+		imageTag, err := getImageTag(imageConf, contextPath, contextHash)
 		if err != nil {
 			return false, fmt.Errorf("Image building failed: %v", err)
 		}
-		if imageConf.Tag != nil {
-			imageTag = *imageConf.Tag
-		}
 
 		imageName, registryConf, err := registry.GetRegistryConfigFromImageConfig(imageConf)
 		if err != nil {
 			return false, fmt.Errorf("GetRegistryConfigFromImageConfig failed: %v", err)
 		}
 
-		engineName := ""
+		engineName := getEngineName(imageConf)
 
-		if imageConf.Build != nil && imageConf.Build.Kaniko != nil {
-			engineName = "kaniko"
-			buildNamespace, err := configutil.GetDefaultNamespace(config)
-			if err != nil {
-				return false, errors.New("Error retrieving default namespace")
+		switch engineName {
+		case engineCustom:
+			command := ""
+			if imageConf.Build.Custom.Command != nil {
+				command = *imageConf.Build.Custom.Command
 			}
 
-			if imageConf.Build.Kaniko.Namespace != nil && *imageConf.Build.Kaniko.Namespace != "" {
-				buildNamespace = *imageConf.Build.Kaniko.Namespace
+			args := []string{}
+			if imageConf.Build.Custom.Args != nil {
+				for _, arg := range *imageConf.Build.Custom.Args {
+					args = append(args, *arg)
+				}
+			}
+
+			imageBuilder, err = custom.NewBuilder(*registryConf.URL, imageName, imageTag, command, args, log)
+			if err != nil {
+				return false, fmt.Errorf("Error creating custom builder: %v", err)
+			}
+		case engineKaniko:
+			buildNamespace, err := getKanikoNamespace(imageConf)
+			if err != nil {
+				return false, err
 			}
 
 			allowInsecurePush := false
@@ -114,23 +339,19 @@ func Build(client *kubernetes.Clientset, generatedConfig *generated.Config, imag
 				allowInsecurePush = *registryConf.Insecure
 			}
 
-			pullSecret := ""
-			if imageConf.Build.Kaniko.PullSecret != nil {
-				pullSecret = *imageConf.Build.Kaniko.PullSecret
-			}
-
 			dockerClient, err := dockerclient.NewClient(false)
 			if err != nil {
 				return false, fmt.Errorf("Error creating docker client: %v", err)
 			}
 
-			imageBuilder, err = kaniko.NewBuilder(*registryConf.URL, pullSecret, imageName, imageTag, (*generatedConfig).ImageTags[imageName], buildNamespace, dockerClient, client, allowInsecurePush)
+			kanikoBuilder, err := kaniko.NewBuilder(*registryConf.URL, imageName, imageTag, buildNamespace, imageConf.Build.Kaniko, dockerClient, client, allowInsecurePush, log)
 			if err != nil {
 				return false, fmt.Errorf("Error creating kaniko builder: %v", err)
 			}
-		} else {
-			engineName = "docker"
 
+			kanikoBuilder.Verbose = config.Build != nil && config.Build.Verbose != nil && *config.Build.Verbose
+			imageBuilder = kanikoBuilder
+		default:
 			preferMinikube := true
 			if imageConf.Build != nil && imageConf.Build.Docker != nil && imageConf.Build.Docker.PreferMinikube != nil {
 				preferMinikube = *imageConf.Build.Docker.PreferMinikube
@@ -141,10 +362,13 @@ func Build(client *kubernetes.Clientset, generatedConfig *generated.Config, imag
 				return false, fmt.Errorf("Error creating docker client: %v", err)
 			}
 
-			imageBuilder, err = docker.NewBuilder(dockerClient, *registryConf.URL, imageName, imageTag)
+			dockerBuilder, err := docker.NewBuilder(dockerClient, *registryConf.URL, imageName, imageTag, log)
 			if err != nil {
 				return false, fmt.Errorf("Error creating docker builder: %v", err)
 			}
+
+			dockerBuilder.Environment = dockerclient.GetClientEnvironment(preferMinikube)
+			imageBuilder = dockerBuilder
 		}
 
 		log.Infof("Building image '%s' with engine '%s'", imageName, engineName)
@@ -166,9 +390,14 @@ func Build(client *kubernetes.Clientset, generatedConfig *generated.Config, imag
 			displayRegistryURL = *registryConf.URL
 		}
 
-		if imageConf.SkipPush == nil || *imageConf.SkipPush == false {
+		// Side-loaded images are not pushed
+		skipPush := (imageConf.SkipPush != nil && *imageConf.SkipPush) || sideLoadTarget != nil
+
+		if skipPush == false {
 			log.StartWait("Authenticating (" + displayRegistryURL + ")")
+			authMutex.Lock()
 			_, err = imageBuilder.Authenticate(username, password, len(username) == 0)
+			authMutex.Unlock()
 			log.StopWait()
 
 			if err != nil {
@@ -191,19 +420,25 @@ func Build(client *kubernetes.Clientset, generatedConfig *generated.Config, imag
 			}
 		}
 
-		err = imageBuilder.BuildImage(contextPath, absoluteDockerfilePath, buildOptions)
+		secrets, cleanupSecrets, err := getBuildSecrets(imageConf)
+		if err != nil {
+			return false, err
+		}
+
+		err = imageBuilder.BuildImage(contextPath, absoluteDockerfilePath, buildOptions, secrets)
+		cleanupSecrets()
 		if err != nil {
 			return false, fmt.Errorf("Error during image build: %v", err)
 		}
 
-		if imageConf.SkipPush == nil || *imageConf.SkipPush == false {
+		if skipPush == false {
 			err = imageBuilder.PushImage()
 			if err != nil {
 				return false, fmt.Errorf("Error during image push: %v", err)
 			}
 
 			log.Info("Image pushed to registry (" + displayRegistryURL + ")")
-		} else {
+		} else if sideLoadTarget == nil {
 			log.Infof("Skip image push for %s", imageName)
 		}
 
@@ -212,7 +447,22 @@ func Build(client *kubernetes.Clientset, generatedConfig *generated.Config, imag
 			imageName = *registryConf.URL + "/" + imageName
 		}
 
+		if sideLoadTarget != nil {
+			log.StartWait(fmt.Sprintf("Loading image into %s cluster %s", sideLoadTarget.Cluster, sideLoadTarget.ClusterName))
+			err = sideLoadImage(sideLoadTarget, imageName+":"+imageTag, log)
+			log.StopWait()
+
+			if err != nil {
+				return false, fmt.Errorf("Error loading image into %s cluster %s: %v", sideLoadTarget.Cluster, sideLoadTarget.ClusterName, err)
+			}
+
+			log.Donef("Loaded image into %s cluster %s", sideLoadTarget.Cluster, sideLoadTarget.ClusterName)
+		}
+
+		generatedConfigMutex.Lock()
 		generatedConfig.ImageTags[imageName] = imageTag
+		generatedConfig.ImageFingerprints[imageRepository] = fingerprint
+		generatedConfigMutex.Unlock()
 
 		log.Done("Done building and pushing image '" + imageName + "'")
 	} else {
@@ -222,43 +472,30 @@ func Build(client *kubernetes.Clientset, generatedConfig *generated.Config, imag
 	return rebuild, nil
 }
 
-func shouldRebuild(runtimeConfig *generated.Config, imageConf *v1.ImageConfig, contextPath, dockerfilePath string, forceRebuild bool) (bool, error) {
-	mustRebuild := true
-
-	// Get dockerfile timestamp
-	dockerfileInfo, err := os.Stat(dockerfilePath)
-	if err != nil {
-		return false, fmt.Errorf("Dockerfile %s missing: %v", dockerfilePath, err)
+// getKanikoNamespace returns the namespace the kaniko build pod of the image is started in
+func getKanikoNamespace(imageConf *v1.ImageConfig) (string, error) {
+	if imageConf.Build.Kaniko.Namespace != nil && *imageConf.Build.Kaniko.Namespace != "" {
+		return *imageConf.Build.Kaniko.Namespace, nil
 	}
 
-	// Hash context path
-	contextDir, relDockerfile, err := build.GetContextFromLocalDir(contextPath, dockerfilePath)
+	namespace, err := configutil.GetDefaultNamespace(configutil.GetConfig())
 	if err != nil {
-		return false, err
+		return "", errors.New("Error retrieving default namespace")
 	}
 
-	excludes, err := build.ReadDockerignore(contextDir)
-	if err != nil {
-		return false, fmt.Errorf("Error reading .dockerignore: %v", err)
-	}
-
-	relDockerfile = archive.CanonicalTarNameForPath(relDockerfile)
-	excludes = build.TrimBuildFilesFromExcludes(excludes, relDockerfile, false)
-	excludes = append(excludes, ".devspace/")
+	return namespace, nil
+}
 
-	hash, err := hash.DirectoryExcludes(contextDir, excludes)
+// getImageRepository returns the registry url and image name, which are used as key in the generated config
+func getImageRepository(imageConf *v1.ImageConfig) (string, error) {
+	imageName, registryConf, err := registry.GetRegistryConfigFromImageConfig(imageConf)
 	if err != nil {
-		return false, fmt.Errorf("Error hashing %s: %v", contextDir, err)
+		return "", fmt.Errorf("GetRegistryConfigFromImageConfig failed: %v", err)
 	}
 
-	// When user has not used -b or --build flags
-	if forceRebuild == false {
-		// only rebuild Docker image when Dockerfile or context has changed since latest build
-		mustRebuild = runtimeConfig.DockerfileTimestamps[dockerfilePath] != dockerfileInfo.ModTime().Unix() || runtimeConfig.DockerContextPaths[contextPath] != hash
+	if *registryConf.URL != "" {
+		return *registryConf.URL + "/" + imageName, nil
 	}
 
-	runtimeConfig.DockerfileTimestamps[dockerfilePath] = dockerfileInfo.ModTime().Unix()
-	runtimeConfig.DockerContextPaths[contextPath] = hash
-
-	return mustRebuild, nil
+	return imageName, nil
 }
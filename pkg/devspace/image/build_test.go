@@ -0,0 +1,235 @@
+package image
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/covexo/devspace/pkg/util/log"
+)
+
+type testBuild struct {
+	duration time.Duration
+	rebuilt  bool
+	err      error
+}
+
+func TestRunBuilds(t *testing.T) {
+	testCases := []struct {
+		name         string
+		dependencies map[string][]string
+		builds       map[string]testBuild
+		parallelism  int
+
+		expectedBuilt    []string
+		expectedRebuilt  bool
+		expectedErr      []string
+		expectedForced   []string
+		expectedParallel int
+	}{
+		{
+			name: "independent images",
+			dependencies: map[string][]string{
+				"a": {},
+				"b": {},
+				"c": {},
+			},
+			builds: map[string]testBuild{
+				"a": {duration: 100 * time.Millisecond},
+				"b": {duration: 10 * time.Millisecond, rebuilt: true},
+				"c": {duration: 50 * time.Millisecond},
+			},
+			parallelism:      3,
+			expectedBuilt:    []string{"a", "b", "c"},
+			expectedRebuilt:  true,
+			expectedParallel: 3,
+		},
+		{
+			name: "parallelism limit",
+			dependencies: map[string][]string{
+				"a": {},
+				"b": {},
+				"c": {},
+			},
+			builds: map[string]testBuild{
+				"a": {duration: 20 * time.Millisecond},
+				"b": {duration: 20 * time.Millisecond},
+				"c": {duration: 20 * time.Millisecond},
+			},
+			parallelism:      2,
+			expectedBuilt:    []string{"a", "b", "c"},
+			expectedParallel: 2,
+		},
+		{
+			name: "chain",
+			dependencies: map[string][]string{
+				"a": {"b"},
+				"b": {"c"},
+				"c": {},
+			},
+			builds: map[string]testBuild{
+				"a": {duration: 10 * time.Millisecond},
+				"b": {duration: 10 * time.Millisecond},
+				"c": {duration: 10 * time.Millisecond, rebuilt: true},
+			},
+			parallelism:      3,
+			expectedBuilt:    []string{"a", "b", "c"},
+			expectedRebuilt:  true,
+			expectedForced:   []string{"b"},
+			expectedParallel: 1,
+		},
+		{
+			name: "error part-way",
+			dependencies: map[string][]string{
+				"a": {},
+				"b": {"a"},
+				"c": {"b"},
+				"d": {},
+				"e": {},
+			},
+			builds: map[string]testBuild{
+				"a": {duration: 10 * time.Millisecond},
+				"b": {duration: 10 * time.Millisecond, err: errors.New("build failed")},
+				"c": {duration: 10 * time.Millisecond},
+				"d": {duration: 200 * time.Millisecond, err: errors.New("slow build failed")},
+				"e": {duration: 200 * time.Millisecond},
+			},
+			parallelism:      3,
+			expectedBuilt:    []string{"a", "b", "d", "e"},
+			expectedErr:      []string{"Error building image b: build failed", "Error building image d: slow build failed"},
+			expectedParallel: 3,
+		},
+	}
+
+	for _, testCase := range testCases {
+		buildOrder, err := getBuildOrder(testCase.dependencies)
+		if err != nil {
+			t.Fatalf("Test case %s: %v", testCase.name, err)
+		}
+
+		mutex := sync.Mutex{}
+		completed := map[string]bool{}
+		built := []string{}
+		forced := []string{}
+		running := 0
+		maxRunning := 0
+
+		rebuilt, err := runBuilds(buildOrder, testCase.dependencies, testCase.parallelism, func(imageName string, dependencyRebuilt bool) (bool, error) {
+			mutex.Lock()
+			for _, dependency := range testCase.dependencies[imageName] {
+				if completed[dependency] == false {
+					t.Errorf("Test case %s: image %s started before its dependency %s was built", testCase.name, imageName, dependency)
+				}
+			}
+			if dependencyRebuilt {
+				forced = append(forced, imageName)
+			}
+
+			running++
+			if running > maxRunning {
+				maxRunning = running
+			}
+			mutex.Unlock()
+
+			build := testCase.builds[imageName]
+			time.Sleep(build.duration)
+
+			mutex.Lock()
+			running--
+			completed[imageName] = true
+			built = append(built, imageName)
+			mutex.Unlock()
+
+			return build.rebuilt, build.err
+		})
+
+		// All builds have to be finished when runBuilds returns
+		mutex.Lock()
+		sort.Strings(built)
+		if strings.Join(built, ",") != strings.Join(testCase.expectedBuilt, ",") {
+			t.Fatalf("Test case %s: expected built images %v, got %v", testCase.name, testCase.expectedBuilt, built)
+		}
+		if strings.Join(forced, ",") != strings.Join(testCase.expectedForced, ",") {
+			t.Fatalf("Test case %s: expected forced rebuilds of %v, got %v", testCase.name, testCase.expectedForced, forced)
+		}
+		if maxRunning != testCase.expectedParallel {
+			t.Fatalf("Test case %s: expected %d parallel builds, got %d", testCase.name, testCase.expectedParallel, maxRunning)
+		}
+		mutex.Unlock()
+
+		if len(testCase.expectedErr) > 0 {
+			if err == nil {
+				t.Fatalf("Test case %s: expected an error", testCase.name)
+			}
+
+			for _, expectedErr := range testCase.expectedErr {
+				if strings.Contains(err.Error(), expectedErr) == false {
+					t.Fatalf("Test case %s: expected error to contain %s, got %v", testCase.name, expectedErr, err)
+				}
+			}
+
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Test case %s: %v", testCase.name, err)
+		}
+
+		if rebuilt != testCase.expectedRebuilt {
+			t.Fatalf("Test case %s: expected rebuilt %v, got %v", testCase.name, testCase.expectedRebuilt, rebuilt)
+		}
+	}
+}
+
+func TestOpenBuildLog(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "devspace-test-build-log-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	oldLogdir := log.Logdir
+	log.Logdir = filepath.Join(tempDir, "logs")
+	defer func() { log.Logdir = oldLogdir }()
+
+	// A build log of a previous build is replaced
+	err = os.MkdirAll(log.Logdir, os.ModePerm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ioutil.WriteFile(filepath.Join(log.Logdir, "build-default.log"), []byte("previous build\n"), 0666)
+
+	images := map[string]string{
+		"default":     "build-default.log",
+		"backend/api": "build-backend-api.log",
+	}
+
+	for imageName := range images {
+		imageLog, buildLog, err := openBuildLog(imageName, log.Discard)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		imageLog.Info("Building image " + imageName)
+		imageLog.Debug("Snapshot of " + imageName)
+		imageLog.Write([]byte("Step 1/1 : FROM " + imageName + "\n"))
+		buildLog.Close()
+	}
+
+	for imageName, filename := range images {
+		data, err := ioutil.ReadFile(filepath.Join(log.Logdir, filename))
+		if err != nil {
+			t.Fatalf("Image %s: %v", imageName, err)
+		}
+
+		expected := "Building image " + imageName + "\nSnapshot of " + imageName + "\nStep 1/1 : FROM " + imageName + "\n"
+		if string(data) != expected {
+			t.Fatalf("Image %s: expected build log\n%s\ngot\n%s", imageName, expected, string(data))
+		}
+	}
+}
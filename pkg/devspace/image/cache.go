@@ -0,0 +1,133 @@
+package image
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/covexo/devspace/pkg/devspace/builder/kaniko"
+	"github.com/covexo/devspace/pkg/devspace/config/configutil"
+	"github.com/covexo/devspace/pkg/devspace/config/v1"
+	"github.com/covexo/devspace/pkg/devspace/registry"
+	"github.com/covexo/devspace/pkg/util/dockerfile"
+	"github.com/covexo/devspace/pkg/util/log"
+	"k8s.io/client-go/kubernetes"
+)
+
+// cacheVolume is a base image cache volume of kaniko and the settings of the pod that fills it
+type cacheVolume struct {
+	Namespace   string
+	VolumeClaim string
+	PullSecret  string
+	WarmerImage string
+}
+
+// WarmCache pulls the base images of all images that are built with kaniko and a cache volume claim into the
+// cache volumes. Base images that are built from this config are skipped, because they change with every build
+func WarmCache(client *kubernetes.Clientset, log log.Logger) error {
+	config := configutil.GetConfig()
+
+	images := map[string]*v1.ImageConfig{}
+	if config.Images != nil {
+		images = *config.Images
+	}
+
+	builtRepositories := map[string]bool{}
+	imageNames := []string{}
+	for imageName, imageConf := range images {
+		repository, err := getRepository(registry.GetImageURL(nil, imageConf, false))
+		if err == nil {
+			builtRepositories[repository] = true
+		}
+
+		imageNames = append(imageNames, imageName)
+	}
+
+	sort.Strings(imageNames)
+
+	volumes := []cacheVolume{}
+	baseImages := map[cacheVolume][]string{}
+
+	for _, imageName := range imageNames {
+		imageConf := images[imageName]
+		if isBuildDisabled(imageConf) || getEngineName(imageConf) != engineKaniko {
+			continue
+		}
+
+		kanikoConfig := imageConf.Build.Kaniko
+		if kanikoConfig.CacheVolumeClaim == nil || *kanikoConfig.CacheVolumeClaim == "" {
+			continue
+		}
+
+		namespace, err := getKanikoNamespace(imageConf)
+		if err != nil {
+			return err
+		}
+
+		_, registryConf, err := registry.GetRegistryConfigFromImageConfig(imageConf)
+		if err != nil {
+			return err
+		}
+
+		// Like the build pod, the warmer uses the pull secret of the registry if no pull secret is configured
+		volume := cacheVolume{
+			Namespace:   namespace,
+			VolumeClaim: *kanikoConfig.CacheVolumeClaim,
+			PullSecret:  registry.GetRegistryAuthSecretName(*registryConf.URL),
+		}
+		if kanikoConfig.PullSecret != nil && *kanikoConfig.PullSecret != "" {
+			volume.PullSecret = *kanikoConfig.PullSecret
+		}
+		if kanikoConfig.WarmerImage != nil {
+			volume.WarmerImage = *kanikoConfig.WarmerImage
+		}
+
+		if _, ok := baseImages[volume]; ok == false {
+			volumes = append(volumes, volume)
+			baseImages[volume] = []string{}
+		}
+
+		dockerfilePath := getDockerfilePath(imageConf)
+		dockerfileBaseImages, err := dockerfile.GetBaseImages(dockerfilePath)
+		if err != nil {
+			return fmt.Errorf("Error reading base images of image %s from %s: %v", imageName, dockerfilePath, err)
+		}
+
+		for _, baseImage := range dockerfileBaseImages {
+			if strings.Contains(baseImage, "$") {
+				log.Warnf("Skipping base image %s of image %s, because it depends on a build argument", baseImage, imageName)
+				continue
+			}
+
+			repository, err := getRepository(baseImage)
+			if err != nil {
+				log.Warnf("Skipping invalid base image %s of image %s: %v", baseImage, imageName, err)
+				continue
+			}
+			if builtRepositories[repository] {
+				continue
+			}
+
+			baseImages[volume] = appendUnique(baseImages[volume], baseImage)
+		}
+	}
+
+	if len(volumes) == 0 {
+		return errors.New("No image is built with kaniko and a cache volume claim (images[].build.kaniko.cacheVolumeClaim)")
+	}
+
+	for _, volume := range volumes {
+		if len(baseImages[volume]) == 0 {
+			log.Infof("No base images to cache in volume %s/%s", volume.Namespace, volume.VolumeClaim)
+			continue
+		}
+
+		err := kaniko.WarmCache(client, volume.Namespace, volume.VolumeClaim, volume.PullSecret, volume.WarmerImage, baseImages[volume], log)
+		if err != nil {
+			return fmt.Errorf("Error warming cache volume %s/%s: %v", volume.Namespace, volume.VolumeClaim, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,225 @@
+package image
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/covexo/devspace/pkg/devspace/config/generated"
+	"github.com/covexo/devspace/pkg/devspace/config/v1"
+	"github.com/covexo/devspace/pkg/devspace/registry"
+	"github.com/covexo/devspace/pkg/util/dockerfile"
+	"github.com/docker/distribution/reference"
+)
+
+// getDependencies returns the images every image depends on. Dependencies are either defined with dependsOn or
+// inferred from FROM statements that reference another image of the config without tag or with the latest tag
+func getDependencies(images map[string]*v1.ImageConfig) (map[string][]string, error) {
+	repositories := map[string]string{}
+	for imageName, imageConf := range images {
+		repository, err := getRepository(registry.GetImageURL(nil, imageConf, false))
+		if err == nil {
+			repositories[repository] = imageName
+		}
+	}
+
+	dependencies := map[string][]string{}
+	for imageName, imageConf := range images {
+		dependencies[imageName] = []string{}
+
+		if imageConf.DependsOn != nil {
+			for _, dependency := range *imageConf.DependsOn {
+				if _, ok := images[*dependency]; ok == false {
+					return nil, fmt.Errorf("Image %s depends on image %s, which does not exist", imageName, *dependency)
+				}
+				if *dependency == imageName {
+					return nil, fmt.Errorf("Image %s depends on itself", imageName)
+				}
+
+				dependencies[imageName] = appendUnique(dependencies[imageName], *dependency)
+			}
+		}
+
+		// Missing Dockerfiles are reported when the image is built
+		baseImages, err := dockerfile.GetBaseImages(getDockerfilePath(imageConf))
+		if err != nil {
+			continue
+		}
+
+		for _, baseImage := range baseImages {
+			repository, ok := getUnpinnedRepository(baseImage)
+			if ok == false {
+				continue
+			}
+
+			if dependency, ok := repositories[repository]; ok && dependency != imageName {
+				dependencies[imageName] = appendUnique(dependencies[imageName], dependency)
+			}
+		}
+	}
+
+	return dependencies, nil
+}
+
+// getBuildOrder sorts the images topologically, so that every image comes after its dependencies. Images without
+// dependencies between each other are sorted by name
+func getBuildOrder(dependencies map[string][]string) ([]string, error) {
+	buildOrder := make([]string, 0, len(dependencies))
+	added := map[string]bool{}
+
+	for len(buildOrder) < len(dependencies) {
+		ready := []string{}
+		for imageName, imageDependencies := range dependencies {
+			if added[imageName] {
+				continue
+			}
+
+			isReady := true
+			for _, dependency := range imageDependencies {
+				if added[dependency] == false {
+					isReady = false
+					break
+				}
+			}
+
+			if isReady {
+				ready = append(ready, imageName)
+			}
+		}
+
+		if len(ready) == 0 {
+			remaining := []string{}
+			for imageName := range dependencies {
+				if added[imageName] == false {
+					remaining = append(remaining, imageName)
+				}
+			}
+
+			sort.Strings(remaining)
+			return nil, fmt.Errorf("Images %s have circular dependencies", strings.Join(remaining, ", "))
+		}
+
+		sort.Strings(ready)
+		for _, imageName := range ready {
+			added[imageName] = true
+			buildOrder = append(buildOrder, imageName)
+		}
+	}
+
+	return buildOrder, nil
+}
+
+// createDependentDockerfile writes a copy of the Dockerfile to a temporary directory, in which the FROM statements
+// that reference the given dependencies use the currently built tag. Returns an empty path if nothing was replaced
+func createDependentDockerfile(generatedConfig *generated.Config, dockerfilePath string, dependencies []*v1.ImageConfig) (string, error) {
+	if len(dependencies) == 0 {
+		return "", nil
+	}
+
+	images := map[string]string{}
+
+	generatedConfigMutex.Lock()
+	for _, dependency := range dependencies {
+		repository, err := getRepository(registry.GetImageURL(generatedConfig, dependency, false))
+		if err != nil {
+			continue
+		}
+
+		image := registry.GetImageURL(generatedConfig, dependency, true)
+		if strings.HasSuffix(image, ":") == false {
+			images[repository] = image
+		}
+	}
+	generatedConfigMutex.Unlock()
+
+	data, err := ioutil.ReadFile(dockerfilePath)
+	if err != nil {
+		// Custom builds may have no Dockerfile
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+
+		return "", err
+	}
+
+	replaced := false
+	data = dockerfile.ReplaceBaseImages(data, func(baseImage string) string {
+		repository, ok := getUnpinnedRepository(baseImage)
+		if ok == false {
+			return baseImage
+		}
+
+		if image, ok := images[repository]; ok {
+			replaced = true
+			return image
+		}
+
+		return baseImage
+	})
+	if replaced == false {
+		return "", nil
+	}
+
+	tempDir, err := ioutil.TempDir("", "devspace-dockerfile-")
+	if err != nil {
+		return "", err
+	}
+
+	// The Dockerfile keeps its name, so that the build output references the file the user knows
+	tempDockerfilePath := filepath.Join(tempDir, filepath.Base(dockerfilePath))
+	err = ioutil.WriteFile(tempDockerfilePath, data, 0666)
+	if err != nil {
+		os.RemoveAll(tempDir)
+		return "", err
+	}
+
+	return tempDockerfilePath, nil
+}
+
+func getDockerfilePath(imageConf *v1.ImageConfig) string {
+	if imageConf.Build != nil && imageConf.Build.DockerfilePath != nil {
+		return *imageConf.Build.DockerfilePath
+	}
+
+	return "./Dockerfile"
+}
+
+func getRepository(image string) (string, error) {
+	ref, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return "", err
+	}
+
+	return ref.Name(), nil
+}
+
+// getUnpinnedRepository returns the repository of an image without tag or with the latest tag. Images with another
+// tag or a digest are pinned to a specific version and don't depend on the currently built image
+func getUnpinnedRepository(image string) (string, bool) {
+	ref, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return "", false
+	}
+
+	if _, ok := ref.(reference.Digested); ok {
+		return "", false
+	}
+	if tagged, ok := ref.(reference.Tagged); ok && tagged.Tag() != "latest" {
+		return "", false
+	}
+
+	return ref.Name(), true
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, existing := range values {
+		if existing == value {
+			return values
+		}
+	}
+
+	return append(values, value)
+}
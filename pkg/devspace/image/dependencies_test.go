@@ -0,0 +1,71 @@
+package image
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetBuildOrder(t *testing.T) {
+	testCases := []struct {
+		name          string
+		dependencies  map[string][]string
+		expectedOrder []string
+		expectedErr   bool
+	}{
+		{
+			name: "independent images are sorted by name",
+			dependencies: map[string][]string{
+				"c": {},
+				"a": {},
+				"b": {},
+			},
+			expectedOrder: []string{"a", "b", "c"},
+		},
+		{
+			name: "chain",
+			dependencies: map[string][]string{
+				"a": {"b"},
+				"b": {"c"},
+				"c": {},
+			},
+			expectedOrder: []string{"c", "b", "a"},
+		},
+		{
+			name: "diamond",
+			dependencies: map[string][]string{
+				"app":    {"node", "python"},
+				"node":   {"base"},
+				"python": {"base"},
+				"base":   {},
+			},
+			expectedOrder: []string{"base", "node", "python", "app"},
+		},
+		{
+			name: "circular dependencies",
+			dependencies: map[string][]string{
+				"a": {"b"},
+				"b": {"a"},
+				"c": {},
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		buildOrder, err := getBuildOrder(testCase.dependencies)
+		if testCase.expectedErr {
+			if err == nil {
+				t.Fatalf("Test case %s: expected an error, got build order %v", testCase.name, buildOrder)
+			}
+
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Test case %s: %v", testCase.name, err)
+		}
+
+		if reflect.DeepEqual(buildOrder, testCase.expectedOrder) == false {
+			t.Fatalf("Test case %s: expected build order %v, got %v", testCase.name, testCase.expectedOrder, buildOrder)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package image
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/covexo/devspace/pkg/devspace/config/v1"
+	dockerclient "github.com/covexo/devspace/pkg/devspace/docker"
+	"github.com/covexo/devspace/pkg/devspace/registry"
+	"github.com/covexo/devspace/pkg/util/dockerfile"
+	"github.com/covexo/devspace/pkg/util/hash"
+	"github.com/covexo/devspace/pkg/util/log"
+	"github.com/docker/cli/cli/command/image/build"
+	"github.com/docker/distribution/reference"
+	"github.com/docker/docker/pkg/archive"
+)
+
+// buildFingerprint contains everything that influences the built image. An image is rebuilt if its fingerprint changes
+type buildFingerprint struct {
+	Image            string             `json:"image"`
+	Tag              *string            `json:"tag,omitempty"`
+	TagStrategy      *string            `json:"tagStrategy,omitempty"`
+	SkipPush         *bool              `json:"skipPush,omitempty"`
+	SideLoad         *sideLoadTarget    `json:"sideLoad,omitempty"`
+	Engine           string             `json:"engine"`
+	Docker           *v1.DockerConfig   `json:"docker,omitempty"`
+	Kaniko           *v1.KanikoConfig   `json:"kaniko,omitempty"`
+	Custom           *v1.CustomConfig   `json:"custom,omitempty"`
+	Options          *v1.BuildOptions   `json:"options,omitempty"`
+	Dockerfile       string             `json:"dockerfile"`
+	Context          string             `json:"context"`
+	BaseImageDigests *map[string]string `json:"baseImageDigests,omitempty"`
+}
+
+// getBuildFingerprint calculates the fingerprint of a build and returns the hash of the context as well. buildDockerfilePath
+// is the Dockerfile the image is actually built with, e.g. with replaced base image tags. sideLoad is part of the
+// fingerprint, because switching between pushing and side-loading requires a new build
+func getBuildFingerprint(imageConf *v1.ImageConfig, sideLoad *sideLoadTarget, contextPath, dockerfilePath, buildDockerfilePath string, log log.Logger) (string, string, error) {
+	dockerfileData, err := ioutil.ReadFile(buildDockerfilePath)
+	if err != nil {
+		// Custom commands don't necessarily need a Dockerfile (e.g. jib or bazel)
+		if os.IsNotExist(err) == false || getEngineName(imageConf) != engineCustom {
+			return "", "", fmt.Errorf("Dockerfile %s missing: %v", dockerfilePath, err)
+		}
+
+		dockerfilePath = ""
+	}
+
+	contextHash, err := getContextHash(contextPath, dockerfilePath)
+	if err != nil {
+		return "", "", err
+	}
+
+	fingerprint := &buildFingerprint{
+		Image:       registry.GetImageURL(nil, imageConf, false),
+		Tag:         imageConf.Tag,
+		TagStrategy: imageConf.TagStrategy,
+		SkipPush:    imageConf.SkipPush,
+		SideLoad:    sideLoad,
+		Engine:      getEngineName(imageConf),
+		Dockerfile:  fmt.Sprintf("%x", sha256.Sum256(dockerfile.NormalizeNewlines(dockerfileData))),
+		Context:     contextHash,
+	}
+
+	if imageConf.Build != nil {
+		fingerprint.Docker = imageConf.Build.Docker
+		fingerprint.Kaniko = imageConf.Build.Kaniko
+		fingerprint.Custom = imageConf.Build.Custom
+		fingerprint.Options = imageConf.Build.Options
+
+		if imageConf.Build.CheckBaseImageDigest != nil && *imageConf.Build.CheckBaseImageDigest {
+			fingerprint.BaseImageDigests = getBaseImageDigests(imageConf, buildDockerfilePath, log)
+		}
+	}
+
+	data, err := json.Marshal(fingerprint)
+	if err != nil {
+		return "", "", err
+	}
+
+	return fmt.Sprintf("%x", sha256.Sum256(data)), contextHash, nil
+}
+
+// getContextHash hashes the files of the context that are not excluded by the .dockerignore. dockerfilePath is empty
+// for custom builds without Dockerfile
+func getContextHash(contextPath, dockerfilePath string) (string, error) {
+	contextDir := contextPath
+	relDockerfile := ""
+
+	if dockerfilePath != "" {
+		var err error
+
+		contextDir, relDockerfile, err = build.GetContextFromLocalDir(contextPath, dockerfilePath)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	excludes, err := build.ReadDockerignore(contextDir)
+	if err != nil {
+		return "", fmt.Errorf("Error reading .dockerignore: %v", err)
+	}
+
+	if relDockerfile != "" {
+		relDockerfile = archive.CanonicalTarNameForPath(relDockerfile)
+		excludes = build.TrimBuildFilesFromExcludes(excludes, relDockerfile, false)
+	}
+
+	excludes = append(excludes, ".devspace/")
+
+	contextHash, err := hash.DirectoryExcludes(contextDir, excludes)
+	if err != nil {
+		return "", fmt.Errorf("Error hashing %s: %v", contextDir, err)
+	}
+
+	return contextHash, nil
+}
+
+// getBaseImageDigests resolves the digests of the base images, so that a new version of a base image with the same tag
+// triggers a rebuild. Base images that cannot be resolved (e.g. without network access) are ignored with a warning
+func getBaseImageDigests(imageConf *v1.ImageConfig, dockerfilePath string, log log.Logger) *map[string]string {
+	digests := map[string]string{}
+
+	baseImages, err := dockerfile.GetBaseImages(dockerfilePath)
+	if err != nil {
+		log.Warnf("Error reading base images from %s: %v", dockerfilePath, err)
+		return &digests
+	}
+
+	preferMinikube := true
+	if imageConf.Build != nil && imageConf.Build.Docker != nil && imageConf.Build.Docker.PreferMinikube != nil {
+		preferMinikube = *imageConf.Build.Docker.PreferMinikube
+	}
+
+	client, err := dockerclient.NewClient(preferMinikube)
+	if err != nil {
+		log.Warnf("Cannot resolve base image digests: error creating docker client: %v", err)
+		return &digests
+	}
+
+	for _, baseImage := range baseImages {
+		ref, err := reference.ParseNormalizedNamed(baseImage)
+		if err != nil {
+			// Base images from build args cannot be resolved
+			continue
+		}
+
+		// The digest is already part of the Dockerfile
+		if _, ok := ref.(reference.Digested); ok {
+			continue
+		}
+
+		digest, err := dockerclient.GetImageDigest(client, baseImage)
+		if err != nil {
+			log.Warnf("Cannot resolve digest of base image %s: %v", baseImage, err)
+			continue
+		}
+
+		digests[baseImage] = digest
+	}
+
+	return &digests
+}
+
+// getEngineName returns the name of the engine the image is built with
+func getEngineName(imageConf *v1.ImageConfig) string {
+	if imageConf.Build != nil && imageConf.Build.Custom != nil {
+		return engineCustom
+	}
+	if imageConf.Build != nil && imageConf.Build.Kaniko != nil {
+		return engineKaniko
+	}
+
+	return engineDocker
+}
@@ -0,0 +1,137 @@
+package image
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/covexo/devspace/pkg/devspace/config/configutil"
+	"github.com/covexo/devspace/pkg/devspace/config/v1"
+	"github.com/covexo/devspace/pkg/util/log"
+)
+
+func TestGetBuildFingerprint(t *testing.T) {
+	testCases := []struct {
+		name            string
+		change          func(contextPath string, imageConf *v1.ImageConfig)
+		expectedChanged bool
+	}{
+		{
+			name:   "nothing changed",
+			change: func(contextPath string, imageConf *v1.ImageConfig) {},
+		},
+		{
+			name: "touched file",
+			change: func(contextPath string, imageConf *v1.ImageConfig) {
+				os.Chtimes(filepath.Join(contextPath, "main.go"), time.Now().Add(time.Hour), time.Now().Add(time.Hour))
+			},
+		},
+		{
+			name: "changed file excluded by .dockerignore",
+			change: func(contextPath string, imageConf *v1.ImageConfig) {
+				ioutil.WriteFile(filepath.Join(contextPath, "ignored.log"), []byte("changed"), 0666)
+			},
+		},
+		{
+			name: "changed file",
+			change: func(contextPath string, imageConf *v1.ImageConfig) {
+				ioutil.WriteFile(filepath.Join(contextPath, "main.go"), []byte("package main // changed"), 0666)
+			},
+			expectedChanged: true,
+		},
+		{
+			name: "new file",
+			change: func(contextPath string, imageConf *v1.ImageConfig) {
+				ioutil.WriteFile(filepath.Join(contextPath, "util.go"), []byte("package main"), 0666)
+			},
+			expectedChanged: true,
+		},
+		{
+			name: "changed Dockerfile",
+			change: func(contextPath string, imageConf *v1.ImageConfig) {
+				ioutil.WriteFile(filepath.Join(contextPath, "Dockerfile"), []byte("FROM alpine:3.8\nCOPY . /app"), 0666)
+			},
+			expectedChanged: true,
+		},
+		{
+			name: "changed build options",
+			change: func(contextPath string, imageConf *v1.ImageConfig) {
+				imageConf.Build.Options = &v1.BuildOptions{
+					Target: configutil.String("production"),
+				}
+			},
+			expectedChanged: true,
+		},
+		{
+			name: "changed tag strategy",
+			change: func(contextPath string, imageConf *v1.ImageConfig) {
+				imageConf.TagStrategy = configutil.String(TagStrategyGitCommit)
+			},
+			expectedChanged: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		contextPath := createTestContext(t)
+		defer os.RemoveAll(contextPath)
+
+		imageConf := &v1.ImageConfig{
+			Name:  configutil.String("test/image"),
+			Build: &v1.BuildConfig{},
+		}
+
+		before := getTestFingerprint(t, contextPath, imageConf)
+		testCase.change(contextPath, imageConf)
+		after := getTestFingerprint(t, contextPath, imageConf)
+
+		if (before != after) != testCase.expectedChanged {
+			t.Fatalf("Test case %s: expected fingerprint changed to be %v, got fingerprints %s and %s", testCase.name, testCase.expectedChanged, before, after)
+		}
+	}
+}
+
+func TestBuildFingerprintIsIndependentOfLocation(t *testing.T) {
+	imageConf := &v1.ImageConfig{
+		Name: configutil.String("test/image"),
+	}
+
+	firstContext := createTestContext(t)
+	defer os.RemoveAll(firstContext)
+
+	secondContext := createTestContext(t)
+	defer os.RemoveAll(secondContext)
+
+	first := getTestFingerprint(t, firstContext, imageConf)
+	second := getTestFingerprint(t, secondContext, imageConf)
+
+	if first != second {
+		t.Fatalf("Expected the same fingerprint for equal contexts in different directories, got %s and %s", first, second)
+	}
+}
+
+func createTestContext(t *testing.T) string {
+	contextPath, err := ioutil.TempDir("", "devspace-test-context-")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ioutil.WriteFile(filepath.Join(contextPath, "Dockerfile"), []byte("FROM alpine\nCOPY . /app"), 0666)
+	ioutil.WriteFile(filepath.Join(contextPath, ".dockerignore"), []byte("*.log"), 0666)
+	ioutil.WriteFile(filepath.Join(contextPath, "main.go"), []byte("package main"), 0666)
+	ioutil.WriteFile(filepath.Join(contextPath, "ignored.log"), []byte("log"), 0666)
+
+	return contextPath
+}
+
+func getTestFingerprint(t *testing.T, contextPath string, imageConf *v1.ImageConfig) string {
+	dockerfilePath := filepath.Join(contextPath, "Dockerfile")
+
+	fingerprint, _, err := getBuildFingerprint(imageConf, nil, contextPath, dockerfilePath, dockerfilePath, log.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return fingerprint
+}
@@ -0,0 +1,99 @@
+package image
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/covexo/devspace/pkg/devspace/builder"
+	"github.com/covexo/devspace/pkg/devspace/config/v1"
+)
+
+// getBuildSecrets returns the build secrets of the image. Secrets from environment variables are written to a
+// temporary directory, which is removed by the returned cleanup function
+func getBuildSecrets(imageConf *v1.ImageConfig) (*builder.BuildSecrets, func(), error) {
+	cleanup := func() {}
+	if imageConf.Build == nil || imageConf.Build.Options == nil {
+		return nil, cleanup, nil
+	}
+
+	options := imageConf.Build.Options
+	secrets := &builder.BuildSecrets{
+		Files: map[string]string{},
+		SSH:   options.SSH != nil && *options.SSH,
+	}
+
+	if options.Secrets == nil {
+		return secrets, cleanup, nil
+	}
+
+	secretDir := ""
+	cleanup = func() {
+		if secretDir != "" {
+			os.RemoveAll(secretDir)
+		}
+	}
+
+	for _, secret := range *options.Secrets {
+		if secret.ID == nil || *secret.ID == "" {
+			cleanup()
+			return nil, func() {}, fmt.Errorf("Build secret without id")
+		}
+
+		id := *secret.ID
+		if _, ok := secrets.Files[id]; ok {
+			cleanup()
+			return nil, func() {}, fmt.Errorf("Build secret %s is defined twice", id)
+		}
+
+		if (secret.File == nil) == (secret.Env == nil) {
+			cleanup()
+			return nil, func() {}, fmt.Errorf("Build secret %s has to define either file or env", id)
+		}
+
+		if secret.File != nil {
+			file, err := filepath.Abs(*secret.File)
+			if err != nil {
+				cleanup()
+				return nil, func() {}, fmt.Errorf("Couldn't determine absolute path for %s", *secret.File)
+			}
+
+			_, err = os.Stat(file)
+			if err != nil {
+				cleanup()
+				return nil, func() {}, fmt.Errorf("Build secret %s: %v", id, err)
+			}
+
+			secrets.Files[id] = file
+			continue
+		}
+
+		value, ok := os.LookupEnv(*secret.Env)
+		if ok == false {
+			cleanup()
+			return nil, func() {}, fmt.Errorf("Build secret %s: environment variable %s is not set", id, *secret.Env)
+		}
+
+		// The secret is written to a file, because the builders only accept secret files
+		if secretDir == "" {
+			dir, err := ioutil.TempDir("", "devspace-secrets-")
+			if err != nil {
+				return nil, func() {}, fmt.Errorf("Unable to create secrets directory: %v", err)
+			}
+
+			secretDir = dir
+		}
+
+		file := filepath.Join(secretDir, fmt.Sprintf("secret-%d", len(secrets.Files)))
+		err := ioutil.WriteFile(file, []byte(value), 0600)
+		if err != nil {
+			cleanup()
+			return nil, func() {}, fmt.Errorf("Unable to write build secret %s: %v", id, err)
+		}
+
+		secrets.Files[id] = file
+	}
+
+	return secrets, cleanup, nil
+}
@@ -0,0 +1,187 @@
+package image
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/covexo/devspace/pkg/devspace/config/configutil"
+	"github.com/covexo/devspace/pkg/devspace/config/v1"
+)
+
+func TestGetBuildSecrets(t *testing.T) {
+	tempDir, restore := setTestTempDir(t)
+	defer restore()
+
+	secretFile := filepath.Join(tempDir, "npmrc")
+	ioutil.WriteFile(secretFile, []byte("token"), 0600)
+
+	os.Setenv("DEVSPACE_TEST_SECRET", "env-token")
+	defer os.Unsetenv("DEVSPACE_TEST_SECRET")
+
+	testCases := []struct {
+		name        string
+		secrets     []*v1.BuildSecretConfig
+		expectedErr bool
+	}{
+		{
+			name: "file and env secrets",
+			secrets: []*v1.BuildSecretConfig{
+				{ID: configutil.String("npmrc"), File: configutil.String(secretFile)},
+				{ID: configutil.String("token"), Env: configutil.String("DEVSPACE_TEST_SECRET")},
+			},
+		},
+		{
+			name: "missing id",
+			secrets: []*v1.BuildSecretConfig{
+				{File: configutil.String(secretFile)},
+			},
+			expectedErr: true,
+		},
+		{
+			name: "duplicate id",
+			secrets: []*v1.BuildSecretConfig{
+				{ID: configutil.String("token"), Env: configutil.String("DEVSPACE_TEST_SECRET")},
+				{ID: configutil.String("token"), File: configutil.String(secretFile)},
+			},
+			expectedErr: true,
+		},
+		{
+			name: "file and env",
+			secrets: []*v1.BuildSecretConfig{
+				{ID: configutil.String("token"), File: configutil.String(secretFile), Env: configutil.String("DEVSPACE_TEST_SECRET")},
+			},
+			expectedErr: true,
+		},
+		{
+			name: "neither file nor env",
+			secrets: []*v1.BuildSecretConfig{
+				{ID: configutil.String("token")},
+			},
+			expectedErr: true,
+		},
+		{
+			name: "missing file",
+			secrets: []*v1.BuildSecretConfig{
+				{ID: configutil.String("token"), Env: configutil.String("DEVSPACE_TEST_SECRET")},
+				{ID: configutil.String("npmrc"), File: configutil.String(filepath.Join(tempDir, "missing"))},
+			},
+			expectedErr: true,
+		},
+		{
+			name: "unset environment variable",
+			secrets: []*v1.BuildSecretConfig{
+				{ID: configutil.String("token"), Env: configutil.String("DEVSPACE_TEST_SECRET")},
+				{ID: configutil.String("other"), Env: configutil.String("DEVSPACE_TEST_UNSET")},
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		imageConf := &v1.ImageConfig{
+			Build: &v1.BuildConfig{
+				Options: &v1.BuildOptions{
+					Secrets: &testCase.secrets,
+				},
+			},
+		}
+
+		secrets, cleanup, err := getBuildSecrets(imageConf)
+		if testCase.expectedErr {
+			if err == nil {
+				t.Fatalf("Test case %s: expected an error", testCase.name)
+			}
+		} else {
+			if err != nil {
+				t.Fatalf("Test case %s: %v", testCase.name, err)
+			}
+			if len(secrets.Files) != len(testCase.secrets) {
+				t.Fatalf("Test case %s: expected %d secret files, got %d", testCase.name, len(testCase.secrets), len(secrets.Files))
+			}
+		}
+
+		cleanup()
+
+		// Secrets from environment variables must not be left behind, also if an error occurred
+		secretDirs, _ := filepath.Glob(filepath.Join(tempDir, "devspace-secrets-*"))
+		if len(secretDirs) > 0 {
+			t.Fatalf("Test case %s: expected the secrets directory to be removed, found %v", testCase.name, secretDirs)
+		}
+	}
+}
+
+func TestGetBuildSecretsFromEnv(t *testing.T) {
+	_, restore := setTestTempDir(t)
+	defer restore()
+
+	os.Setenv("DEVSPACE_TEST_SECRET", "env-token")
+	defer os.Unsetenv("DEVSPACE_TEST_SECRET")
+
+	imageConf := &v1.ImageConfig{
+		Build: &v1.BuildConfig{
+			Options: &v1.BuildOptions{
+				Secrets: &[]*v1.BuildSecretConfig{
+					{ID: configutil.String("token"), Env: configutil.String("DEVSPACE_TEST_SECRET")},
+				},
+				SSH: configutil.Bool(true),
+			},
+		},
+	}
+
+	secrets, cleanup, err := getBuildSecrets(imageConf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if secrets.SSH == false {
+		t.Fatalf("Expected ssh forwarding to be enabled")
+	}
+
+	file := secrets.Files["token"]
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "env-token" {
+		t.Fatalf("Expected secret file content env-token, got %s", string(data))
+	}
+
+	stat, err := os.Stat(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stat.Mode().Perm() != 0600 {
+		t.Fatalf("Expected secret file mode 0600, got %v", stat.Mode().Perm())
+	}
+
+	cleanup()
+
+	_, err = os.Stat(filepath.Dir(file))
+	if os.IsNotExist(err) == false {
+		t.Fatalf("Expected the secrets directory to be removed by the cleanup")
+	}
+}
+
+// setTestTempDir uses a new temporary directory as TMPDIR, so that the test can check which temporary files were left
+// behind. The returned function restores TMPDIR and removes the directory
+func setTestTempDir(t *testing.T) (string, func()) {
+	tempDir, err := ioutil.TempDir("", "devspace-test-secrets-")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oldTempDir, hasTempDir := os.LookupEnv("TMPDIR")
+	os.Setenv("TMPDIR", tempDir)
+
+	return tempDir, func() {
+		if hasTempDir {
+			os.Setenv("TMPDIR", oldTempDir)
+		} else {
+			os.Unsetenv("TMPDIR")
+		}
+
+		os.RemoveAll(tempDir)
+	}
+}
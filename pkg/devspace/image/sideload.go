@@ -0,0 +1,134 @@
+package image
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/covexo/devspace/pkg/devspace/config/v1"
+	dockerclient "github.com/covexo/devspace/pkg/devspace/docker"
+	"github.com/covexo/devspace/pkg/devspace/kubectl"
+	"github.com/covexo/devspace/pkg/util/log"
+)
+
+// Local clusters images can be side-loaded into
+const (
+	SideLoadClusterKind = "kind"
+	SideLoadClusterK3d  = "k3d"
+)
+
+// defaultClusterNames are the cluster names the kind and k3d clis use by default
+var defaultClusterNames = map[string]string{
+	SideLoadClusterKind: "kind",
+	SideLoadClusterK3d:  "k3s-default",
+}
+
+// nodeLabels are the docker labels of the node containers, which are used if the cli of the cluster is not installed
+var nodeLabels = map[string]string{
+	SideLoadClusterKind: "io.x-k8s.kind.cluster",
+	SideLoadClusterK3d:  "k3d.cluster",
+}
+
+type sideLoadTarget struct {
+	Cluster     string
+	ClusterName string
+}
+
+// IsSideLoaded returns true if the image is loaded into the cluster nodes instead of being pushed to a registry
+func IsSideLoaded(imageConf *v1.ImageConfig) bool {
+	target, err := getSideLoadTarget(imageConf)
+	return err == nil && target != nil
+}
+
+// getSideLoadTarget returns the local cluster the image is loaded into or nil if the image is pushed. Images built with docker
+// are side-loaded automatically if the kube context belongs to a kind (kind-*) or k3d (k3d-*) cluster
+func getSideLoadTarget(imageConf *v1.ImageConfig) (*sideLoadTarget, error) {
+	return getSideLoadTargetForContext(imageConf, kubectl.GetKubeContext())
+}
+
+func getSideLoadTargetForContext(imageConf *v1.ImageConfig, kubeContext string) (*sideLoadTarget, error) {
+	sideLoad := imageConf.SideLoad
+	if sideLoad != nil && sideLoad.Disabled != nil && *sideLoad.Disabled {
+		return nil, nil
+	}
+
+	target := detectLocalCluster(kubeContext)
+	if sideLoad == nil {
+		// Only images that end up in the local docker daemon can be side-loaded automatically
+		if target == nil || getEngineName(imageConf) != engineDocker {
+			return nil, nil
+		}
+
+		return target, nil
+	}
+
+	if getEngineName(imageConf) == engineKaniko {
+		return nil, fmt.Errorf("Images built with kaniko cannot be side-loaded, because they are not in the local docker daemon")
+	}
+
+	if target == nil {
+		target = &sideLoadTarget{}
+	}
+	if sideLoad.Cluster != nil && *sideLoad.Cluster != target.Cluster {
+		target = &sideLoadTarget{
+			Cluster: *sideLoad.Cluster,
+		}
+	}
+	if sideLoad.ClusterName != nil {
+		target.ClusterName = *sideLoad.ClusterName
+	}
+
+	if _, ok := defaultClusterNames[target.Cluster]; ok == false {
+		return nil, fmt.Errorf("Cannot detect the local cluster from the kube context, please set sideLoad.cluster to %s or %s", SideLoadClusterKind, SideLoadClusterK3d)
+	}
+	if target.ClusterName == "" {
+		target.ClusterName = defaultClusterNames[target.Cluster]
+	}
+
+	return target, nil
+}
+
+func detectLocalCluster(kubeContext string) *sideLoadTarget {
+	for _, cluster := range []string{SideLoadClusterKind, SideLoadClusterK3d} {
+		if strings.HasPrefix(kubeContext, cluster+"-") {
+			return &sideLoadTarget{
+				Cluster:     cluster,
+				ClusterName: strings.TrimPrefix(kubeContext, cluster+"-"),
+			}
+		}
+	}
+
+	return nil
+}
+
+// sideLoadImage loads the image from the local docker daemon into the cluster nodes. The kind or k3d cli is used if it is
+// installed, otherwise the image is imported into the containerd of the node containers directly
+func sideLoadImage(target *sideLoadTarget, image string, log log.Logger) error {
+	if _, err := exec.LookPath(target.Cluster); err == nil {
+		var cmd *exec.Cmd
+
+		switch target.Cluster {
+		case SideLoadClusterKind:
+			cmd = exec.Command("kind", "load", "docker-image", image, "--name", target.ClusterName)
+		case SideLoadClusterK3d:
+			cmd = exec.Command("k3d", "image", "import", image, "--cluster", target.ClusterName)
+		}
+
+		cmd.Stdout = log
+		cmd.Stderr = log
+
+		err := cmd.Run()
+		if err != nil {
+			return fmt.Errorf("%s failed: %v", strings.Join(cmd.Args, " "), err)
+		}
+
+		return nil
+	}
+
+	client, err := dockerclient.NewClient(false)
+	if err != nil {
+		return fmt.Errorf("Error creating docker client: %v", err)
+	}
+
+	return dockerclient.ImportImageIntoNodes(client, image, nodeLabels[target.Cluster]+"="+target.ClusterName, log)
+}
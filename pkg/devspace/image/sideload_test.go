@@ -0,0 +1,179 @@
+package image
+
+import (
+	"testing"
+
+	"github.com/covexo/devspace/pkg/devspace/config/configutil"
+	"github.com/covexo/devspace/pkg/devspace/config/v1"
+)
+
+func TestDetectLocalCluster(t *testing.T) {
+	testCases := []struct {
+		name        string
+		kubeContext string
+		expected    *sideLoadTarget
+	}{
+		{
+			name:        "kind",
+			kubeContext: "kind-dev",
+			expected:    &sideLoadTarget{Cluster: SideLoadClusterKind, ClusterName: "dev"},
+		},
+		{
+			name:        "k3d",
+			kubeContext: "k3d-k3s-default",
+			expected:    &sideLoadTarget{Cluster: SideLoadClusterK3d, ClusterName: "k3s-default"},
+		},
+		{
+			name:        "minikube",
+			kubeContext: "minikube",
+		},
+		{
+			name:        "prefix without dash",
+			kubeContext: "kindergarten",
+		},
+	}
+
+	for _, testCase := range testCases {
+		target := detectLocalCluster(testCase.kubeContext)
+		if (target == nil) != (testCase.expected == nil) {
+			t.Fatalf("Test case %s: expected target %v, got %v", testCase.name, testCase.expected, target)
+		}
+		if target != nil && *target != *testCase.expected {
+			t.Fatalf("Test case %s: expected target %v, got %v", testCase.name, *testCase.expected, *target)
+		}
+	}
+}
+
+func TestGetSideLoadTarget(t *testing.T) {
+	testCases := []struct {
+		name        string
+		kubeContext string
+		imageConf   *v1.ImageConfig
+		expected    *sideLoadTarget
+		expectedErr bool
+	}{
+		{
+			name:        "docker image in kind cluster",
+			kubeContext: "kind-dev",
+			imageConf:   &v1.ImageConfig{},
+			expected:    &sideLoadTarget{Cluster: SideLoadClusterKind, ClusterName: "dev"},
+		},
+		{
+			name:        "docker image in remote cluster",
+			kubeContext: "gke_project_zone_cluster",
+			imageConf:   &v1.ImageConfig{},
+		},
+		{
+			name:        "kaniko image is not detected",
+			kubeContext: "kind-dev",
+			imageConf: &v1.ImageConfig{
+				Build: &v1.BuildConfig{
+					Kaniko: &v1.KanikoConfig{},
+				},
+			},
+		},
+		{
+			name:        "custom image is not detected",
+			kubeContext: "k3d-dev",
+			imageConf: &v1.ImageConfig{
+				Build: &v1.BuildConfig{
+					Custom: &v1.CustomConfig{
+						Command: configutil.String("buildah"),
+					},
+				},
+			},
+		},
+		{
+			name:        "disabled",
+			kubeContext: "kind-dev",
+			imageConf: &v1.ImageConfig{
+				SideLoad: &v1.SideLoadConfig{
+					Disabled: configutil.Bool(true),
+				},
+			},
+		},
+		{
+			name:        "explicit cluster with default cluster name",
+			kubeContext: "dev-cluster",
+			imageConf: &v1.ImageConfig{
+				SideLoad: &v1.SideLoadConfig{
+					Cluster: configutil.String(SideLoadClusterK3d),
+				},
+			},
+			expected: &sideLoadTarget{Cluster: SideLoadClusterK3d, ClusterName: "k3s-default"},
+		},
+		{
+			name:        "explicit cluster overrides detected cluster",
+			kubeContext: "kind-dev",
+			imageConf: &v1.ImageConfig{
+				SideLoad: &v1.SideLoadConfig{
+					Cluster: configutil.String(SideLoadClusterK3d),
+				},
+			},
+			expected: &sideLoadTarget{Cluster: SideLoadClusterK3d, ClusterName: "k3s-default"},
+		},
+		{
+			name:        "explicit cluster name",
+			kubeContext: "kind-dev",
+			imageConf: &v1.ImageConfig{
+				SideLoad: &v1.SideLoadConfig{
+					ClusterName: configutil.String("other"),
+				},
+			},
+			expected: &sideLoadTarget{Cluster: SideLoadClusterKind, ClusterName: "other"},
+		},
+		{
+			name:        "custom image with explicit side-loading",
+			kubeContext: "k3d-dev",
+			imageConf: &v1.ImageConfig{
+				SideLoad: &v1.SideLoadConfig{},
+				Build: &v1.BuildConfig{
+					Custom: &v1.CustomConfig{
+						Command: configutil.String("img"),
+					},
+				},
+			},
+			expected: &sideLoadTarget{Cluster: SideLoadClusterK3d, ClusterName: "dev"},
+		},
+		{
+			name:        "kaniko image with explicit side-loading",
+			kubeContext: "kind-dev",
+			imageConf: &v1.ImageConfig{
+				SideLoad: &v1.SideLoadConfig{},
+				Build: &v1.BuildConfig{
+					Kaniko: &v1.KanikoConfig{},
+				},
+			},
+			expectedErr: true,
+		},
+		{
+			name:        "unknown cluster",
+			kubeContext: "minikube",
+			imageConf: &v1.ImageConfig{
+				SideLoad: &v1.SideLoadConfig{},
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		target, err := getSideLoadTargetForContext(testCase.imageConf, testCase.kubeContext)
+		if testCase.expectedErr {
+			if err == nil {
+				t.Fatalf("Test case %s: expected an error, got target %v", testCase.name, target)
+			}
+
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Test case %s: %v", testCase.name, err)
+		}
+
+		if (target == nil) != (testCase.expected == nil) {
+			t.Fatalf("Test case %s: expected target %v, got %v", testCase.name, testCase.expected, target)
+		}
+		if target != nil && *target != *testCase.expected {
+			t.Fatalf("Test case %s: expected target %v, got %v", testCase.name, *testCase.expected, *target)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package image
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/covexo/devspace/pkg/devspace/config/v1"
+	"github.com/covexo/devspace/pkg/util/randutil"
+)
+
+// Tag strategies that can be used in images.*.tagStrategy. Other values are templates that may combine the
+// strategies and environment variables, e.g. ${gitCommit}-${timestamp}
+const (
+	TagStrategyRandom      = "random"
+	TagStrategyGitCommit   = "gitCommit"
+	TagStrategyTimestamp   = "timestamp"
+	TagStrategyContextHash = "contextHash"
+)
+
+// gitDirtySuffix is appended to the commit sha if the context contains uncommitted changes, followed by the first
+// characters of the context hash, so that every change of the uncommitted files gets a new tag
+const gitDirtySuffix = "-dirty-"
+
+// gitDirtyHashLength is the number of characters of the context hash used in dirty git commit tags
+const gitDirtyHashLength = 7
+
+// contextHashLength is the number of characters of the context hash used in tags
+const contextHashLength = 12
+
+var validTagRegEx = regexp.MustCompile(`^[\w][\w.-]{0,127}$`)
+
+// getImageTag returns the tag for a new build of the image. A fixed tag takes precedence over the tag strategy
+func getImageTag(imageConf *v1.ImageConfig, contextPath, contextHash string) (string, error) {
+	if imageConf.Tag != nil {
+		return *imageConf.Tag, nil
+	}
+
+	tagStrategy := TagStrategyRandom
+	if imageConf.TagStrategy != nil && *imageConf.TagStrategy != "" {
+		tagStrategy = *imageConf.TagStrategy
+	}
+
+	var err error
+	var imageTag string
+
+	if strings.Contains(tagStrategy, "$") {
+		imageTag = os.Expand(tagStrategy, func(name string) string {
+			if err != nil {
+				return ""
+			}
+
+			switch name {
+			case TagStrategyRandom, TagStrategyGitCommit, TagStrategyTimestamp, TagStrategyContextHash:
+				var value string
+
+				value, err = getTagStrategyValue(name, contextPath, contextHash)
+				return value
+			}
+
+			return os.Getenv(name)
+		})
+	} else {
+		imageTag, err = getTagStrategyValue(tagStrategy, contextPath, contextHash)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if validTagRegEx.MatchString(imageTag) == false {
+		return "", fmt.Errorf("Tag strategy %s generated the invalid tag '%s'", tagStrategy, imageTag)
+	}
+
+	return imageTag, nil
+}
+
+func getTagStrategyValue(tagStrategy, contextPath, contextHash string) (string, error) {
+	switch tagStrategy {
+	case TagStrategyRandom:
+		return randutil.GenerateRandomString(7)
+	case TagStrategyGitCommit:
+		return getGitCommitTag(contextPath, contextHash)
+	case TagStrategyTimestamp:
+		return time.Now().UTC().Format("20060102150405"), nil
+	case TagStrategyContextHash:
+		if len(contextHash) > contextHashLength {
+			return contextHash[:contextHashLength], nil
+		}
+
+		return contextHash, nil
+	}
+
+	return "", fmt.Errorf("Unknown tag strategy %s. Please use one of %s, %s, %s, %s or a template like ${%s}-${%s}", tagStrategy, TagStrategyRandom, TagStrategyGitCommit, TagStrategyTimestamp, TagStrategyContextHash, TagStrategyGitCommit, TagStrategyTimestamp)
+}
+
+// getGitCommitTag returns the short sha of the current commit of the repository the context belongs to. The dirty suffix
+// and the context hash are added if the context contains uncommitted or untracked files, because these end up in the
+// image as well
+func getGitCommitTag(contextPath, contextHash string) (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--short=7", "HEAD")
+	cmd.Dir = contextPath
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("Error retrieving git commit of %s (is it a git repository with at least one commit?): %v", contextPath, err)
+	}
+
+	commit := strings.TrimSpace(string(out))
+
+	cmd = exec.Command("git", "status", "--porcelain", "--", ".")
+	cmd.Dir = contextPath
+
+	out, err = cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("Error retrieving git status of %s: %v", contextPath, err)
+	}
+	if strings.TrimSpace(string(out)) != "" {
+		if len(contextHash) > gitDirtyHashLength {
+			contextHash = contextHash[:gitDirtyHashLength]
+		}
+
+		commit += gitDirtySuffix + contextHash
+	}
+
+	return commit, nil
+}
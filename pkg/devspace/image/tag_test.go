@@ -0,0 +1,183 @@
+package image
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/covexo/devspace/pkg/devspace/config/configutil"
+	"github.com/covexo/devspace/pkg/devspace/config/v1"
+)
+
+const testContextHash = "0123456789abcdef0123456789abcdef"
+
+func TestGetImageTag(t *testing.T) {
+	os.Setenv("DEVSPACE_TEST_BRANCH", "feature")
+	defer os.Unsetenv("DEVSPACE_TEST_BRANCH")
+
+	testCases := []struct {
+		name        string
+		imageConf   *v1.ImageConfig
+		expectedTag string
+		expectedRE  string
+		expectedErr bool
+	}{
+		{
+			name: "fixed tag takes precedence",
+			imageConf: &v1.ImageConfig{
+				Tag:         configutil.String("v1"),
+				TagStrategy: configutil.String(TagStrategyContextHash),
+			},
+			expectedTag: "v1",
+		},
+		{
+			name:       "random by default",
+			imageConf:  &v1.ImageConfig{},
+			expectedRE: `^[a-zA-Z0-9]{7}$`,
+		},
+		{
+			name: "timestamp",
+			imageConf: &v1.ImageConfig{
+				TagStrategy: configutil.String(TagStrategyTimestamp),
+			},
+			expectedRE: `^\d{14}$`,
+		},
+		{
+			name: "context hash",
+			imageConf: &v1.ImageConfig{
+				TagStrategy: configutil.String(TagStrategyContextHash),
+			},
+			expectedTag: testContextHash[:contextHashLength],
+		},
+		{
+			name: "template with strategies and environment variables",
+			imageConf: &v1.ImageConfig{
+				TagStrategy: configutil.String("${DEVSPACE_TEST_BRANCH}-${contextHash}-${timestamp}"),
+			},
+			expectedRE: `^feature-` + testContextHash[:contextHashLength] + `-\d{14}$`,
+		},
+		{
+			name: "template with unset environment variable",
+			imageConf: &v1.ImageConfig{
+				TagStrategy: configutil.String("${DEVSPACE_TEST_UNSET}"),
+			},
+			expectedErr: true,
+		},
+		{
+			name: "unknown strategy",
+			imageConf: &v1.ImageConfig{
+				TagStrategy: configutil.String("unknown"),
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		tag, err := getImageTag(testCase.imageConf, ".", testContextHash)
+		if testCase.expectedErr {
+			if err == nil {
+				t.Fatalf("Test case %s: expected an error, got tag %s", testCase.name, tag)
+			}
+
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Test case %s: %v", testCase.name, err)
+		}
+
+		if testCase.expectedTag != "" && tag != testCase.expectedTag {
+			t.Fatalf("Test case %s: expected tag %s, got %s", testCase.name, testCase.expectedTag, tag)
+		}
+		if testCase.expectedRE != "" && regexp.MustCompile(testCase.expectedRE).MatchString(tag) == false {
+			t.Fatalf("Test case %s: expected tag to match %s, got %s", testCase.name, testCase.expectedRE, tag)
+		}
+	}
+}
+
+func TestGetGitCommitTag(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repository, err := ioutil.TempDir("", "devspace-test-git-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(repository)
+
+	runGit := func(args ...string) string {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@test.local"}, args...)...)
+		cmd.Dir = repository
+
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s failed: %v %s", strings.Join(args, " "), err, string(out))
+		}
+
+		return strings.TrimSpace(string(out))
+	}
+
+	runGit("init")
+	ioutil.WriteFile(filepath.Join(repository, "Dockerfile"), []byte("FROM alpine"), 0666)
+	runGit("add", "Dockerfile")
+	runGit("commit", "-m", "initial")
+	commit := runGit("rev-parse", "--short=7", "HEAD")
+
+	tag, err := getGitCommitTag(repository, testContextHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tag != commit {
+		t.Fatalf("Expected tag %s for a clean repository, got %s", commit, tag)
+	}
+
+	// Every change of the uncommitted files has to get a new tag
+	ioutil.WriteFile(filepath.Join(repository, "Dockerfile"), []byte("FROM alpine:3.8"), 0666)
+
+	tag, err = getGitCommitTag(repository, testContextHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tag != commit+"-dirty-"+testContextHash[:gitDirtyHashLength] {
+		t.Fatalf("Expected dirty tag for %s, got %s", commit, tag)
+	}
+
+	otherTag, err := getGitCommitTag(repository, "fedcba9876543210")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if otherTag == tag {
+		t.Fatalf("Expected different dirty tags for different contexts, got %s twice", tag)
+	}
+}
+
+func TestContextHashIsIndependentOfLocation(t *testing.T) {
+	contextHashes := []string{}
+
+	for i := 0; i < 2; i++ {
+		contextPath, err := ioutil.TempDir("", "devspace-test-context-")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(contextPath)
+
+		os.MkdirAll(filepath.Join(contextPath, "src"), 0755)
+		ioutil.WriteFile(filepath.Join(contextPath, "Dockerfile"), []byte("FROM alpine"), 0666)
+		ioutil.WriteFile(filepath.Join(contextPath, "src", "main.go"), []byte("package main"), 0666)
+
+		contextHash, err := getContextHash(contextPath, filepath.Join(contextPath, "Dockerfile"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		contextHashes = append(contextHashes, contextHash)
+	}
+
+	if contextHashes[0] != contextHashes[1] {
+		t.Fatalf("Expected the same context hash for equal contexts in different directories, got %s and %s", contextHashes[0], contextHashes[1])
+	}
+}
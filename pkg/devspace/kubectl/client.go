@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"sort"
 	"sync"
 	"time"
 
@@ -171,29 +172,34 @@ func loadCloudConfig(config *v1.Config, log log.Logger) error {
 // IsMinikube returns true if the Kubernetes cluster is a minikube
 func IsMinikube() bool {
 	if isMinikubeVar == nil {
-		isMinikube := false
-		config := configutil.GetConfig()
-		if config.Cluster.APIServer == nil {
-			if config.Cluster.KubeContext == nil {
-				loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
-				kubeConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{})
-				cfg, err := kubeConfig.RawConfig()
-				if err != nil {
-					return false
-				}
-
-				isMinikube = cfg.CurrentContext == "minikube"
-			} else {
-				isMinikube = *config.Cluster.KubeContext == "minikube"
-			}
-		}
-
+		isMinikube := GetKubeContext() == "minikube"
 		isMinikubeVar = &isMinikube
 	}
 
 	return *isMinikubeVar
 }
 
+// GetKubeContext returns the name of the kube context devspace uses. Returns an empty string if the api server is
+// configured directly or the kube config cannot be loaded
+func GetKubeContext() string {
+	config := configutil.GetConfig()
+	if config.Cluster != nil && config.Cluster.APIServer != nil {
+		return ""
+	}
+	if config.Cluster != nil && config.Cluster.KubeContext != nil {
+		return *config.Cluster.KubeContext
+	}
+
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	kubeConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{})
+	cfg, err := kubeConfig.RawConfig()
+	if err != nil {
+		return ""
+	}
+
+	return cfg.CurrentContext
+}
+
 // GetNewestRunningPod retrieves the first pod that is found that has the status "Running" using the label selector string
 func GetNewestRunningPod(kubectl *kubernetes.Clientset, labelSelector, namespace string, maxWaiting time.Duration) (*k8sv1.Pod, error) {
 	config := configutil.GetConfig()
@@ -207,6 +213,9 @@ func GetNewestRunningPod(kubectl *kubernetes.Clientset, labelSelector, namespace
 		namespace = defaultNamespace
 	}
 
+	reporter := NewPodEventReporter(kubectl, log.GetInstance())
+	pods := []*k8sv1.Pod{}
+
 	waitingInterval := 1 * time.Second
 	for maxWaiting > 0 {
 		time.Sleep(waitingInterval)
@@ -218,6 +227,9 @@ func GetNewestRunningPod(kubectl *kubernetes.Clientset, labelSelector, namespace
 			return nil, err
 		}
 
+		pods = getPodPointers(podList)
+		reporter.Report(pods)
+
 		if podList.Size() > 0 && len(podList.Items) > 0 {
 			// Get Pod with latest creation timestamp
 			var selectedPod *k8sv1.Pod
@@ -234,6 +246,7 @@ func GetNewestRunningPod(kubectl *kubernetes.Clientset, labelSelector, namespace
 				if podStatus == "Running" {
 					return selectedPod, nil
 				} else if podStatus == "Error" || podStatus == "Unknown" || podStatus == "ImagePullBackOff" || podStatus == "CrashLoopBackOff" || podStatus == "RunContainerError" || podStatus == "ErrImagePull" || podStatus == "CreateContainerConfigError" || podStatus == "InvalidImageName" {
+					reporter.PrintSummary(labelSelector, namespace, []*k8sv1.Pod{selectedPod})
 					return nil, fmt.Errorf("Selected Pod(s) cannot start (Status: %s)", podStatus)
 				}
 			}
@@ -243,9 +256,76 @@ func GetNewestRunningPod(kubectl *kubernetes.Clientset, labelSelector, namespace
 		maxWaiting -= waitingInterval * 2
 	}
 
+	reporter.PrintSummary(labelSelector, namespace, pods)
 	return nil, fmt.Errorf("Waiting for pod with selector %s in namespace %s timed out", labelSelector, namespace)
 }
 
+// GetRunningPods waits until at least one pod matching the label selector is running and returns all running pods sorted by creation timestamp (newest first)
+func GetRunningPods(kubectl *kubernetes.Clientset, labelSelector, namespace string, maxWaiting time.Duration) ([]*k8sv1.Pod, error) {
+	config := configutil.GetConfig()
+
+	if namespace == "" {
+		defaultNamespace, err := configutil.GetDefaultNamespace(config)
+		if err != nil {
+			return nil, err
+		}
+
+		namespace = defaultNamespace
+	}
+
+	reporter := NewPodEventReporter(kubectl, log.GetInstance())
+	pods := []*k8sv1.Pod{}
+
+	waitingInterval := 1 * time.Second
+	for maxWaiting > 0 {
+		time.Sleep(waitingInterval)
+
+		podList, err := kubectl.Core().Pods(namespace).List(metav1.ListOptions{
+			LabelSelector: labelSelector,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		pods = getPodPointers(podList)
+		reporter.Report(pods)
+
+		runningPods := []*k8sv1.Pod{}
+		for index := range podList.Items {
+			if GetPodStatus(&podList.Items[index]) == "Running" {
+				runningPods = append(runningPods, &podList.Items[index])
+			}
+		}
+
+		if len(runningPods) > 0 {
+			sort.Slice(runningPods, func(i, j int) bool {
+				if runningPods[i].CreationTimestamp.Equal(&runningPods[j].CreationTimestamp) {
+					return runningPods[i].Name < runningPods[j].Name
+				}
+
+				return runningPods[j].CreationTimestamp.Before(&runningPods[i].CreationTimestamp)
+			})
+
+			return runningPods, nil
+		}
+
+		time.Sleep(waitingInterval)
+		maxWaiting -= waitingInterval * 2
+	}
+
+	reporter.PrintSummary(labelSelector, namespace, pods)
+	return nil, fmt.Errorf("Waiting for pod with selector %s in namespace %s timed out", labelSelector, namespace)
+}
+
+func getPodPointers(podList *k8sv1.PodList) []*k8sv1.Pod {
+	pods := make([]*k8sv1.Pod, 0, len(podList.Items))
+	for index := range podList.Items {
+		pods = append(pods, &podList.Items[index])
+	}
+
+	return pods
+}
+
 // GetPodStatus returns the pod status as a string
 // Taken from https://github.com/kubernetes/kubernetes/pkg/printers/internalversion/printers.go
 func GetPodStatus(pod *k8sv1.Pod) string {
@@ -0,0 +1,193 @@
+package kubectl
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/covexo/devspace/pkg/util/randutil"
+	k8sv1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ErrEphemeralContainersNotSupported is returned if the cluster does not support ephemeral containers
+var ErrEphemeralContainersNotSupported = errors.New("Ephemeral containers are not supported by the cluster")
+
+// ephemeralContainer is the part of the ephemeral container spec we need. The vendored api version doesn't include the type yet
+type ephemeralContainer struct {
+	Name                string   `json:"name"`
+	Image               string   `json:"image"`
+	Command             []string `json:"command,omitempty"`
+	Stdin               bool     `json:"stdin"`
+	TTY                 bool     `json:"tty"`
+	TargetContainerName string   `json:"targetContainerName,omitempty"`
+}
+
+type ephemeralContainerPod struct {
+	Spec struct {
+		EphemeralContainers []ephemeralContainer `json:"ephemeralContainers,omitempty"`
+	} `json:"spec"`
+	Status struct {
+		EphemeralContainerStatuses []k8sv1.ContainerStatus `json:"ephemeralContainerStatuses,omitempty"`
+	} `json:"status"`
+}
+
+// GetDebugContainerName returns a new random name for a debug container
+func GetDebugContainerName() (string, error) {
+	random, err := randutil.GenerateRandomString(5)
+	if err != nil {
+		return "", err
+	}
+
+	return "debug-" + strings.ToLower(random), nil
+}
+
+// AddEphemeralContainer adds an ephemeral debug container to the pod that shares the process namespace of the target container
+// and waits until it is running. Returns ErrEphemeralContainersNotSupported if the cluster doesn't support ephemeral containers
+func AddEphemeralContainer(client *kubernetes.Clientset, pod *k8sv1.Pod, name, image string, command []string, targetContainer string, maxWaiting time.Duration) error {
+	patch := ephemeralContainerPod{}
+	patch.Spec.EphemeralContainers = []ephemeralContainer{
+		{
+			Name:                name,
+			Image:               image,
+			Command:             command,
+			Stdin:               true,
+			TTY:                 true,
+			TargetContainerName: targetContainer,
+		},
+	}
+
+	body, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.Core().RESTClient().Patch(types.StrategicMergePatchType).
+		Namespace(pod.Namespace).
+		Resource("pods").
+		Name(pod.Name).
+		SubResource("ephemeralcontainers").
+		Body(body).
+		Do().
+		Raw()
+	if err != nil {
+		if kerrors.IsNotFound(err) || kerrors.IsMethodNotSupported(err) || kerrors.IsBadRequest(err) || kerrors.IsUnsupportedMediaType(err) {
+			return ErrEphemeralContainersNotSupported
+		}
+
+		return err
+	}
+
+	return waitForContainer(maxWaiting, pod.Namespace, pod.Name, name, func() ([]k8sv1.ContainerStatus, error) {
+		raw, err := client.Core().RESTClient().Get().
+			Namespace(pod.Namespace).
+			Resource("pods").
+			Name(pod.Name).
+			Do().
+			Raw()
+		if err != nil {
+			return nil, err
+		}
+
+		currentPod := &ephemeralContainerPod{}
+		err = json.Unmarshal(raw, currentPod)
+		if err != nil {
+			return nil, err
+		}
+
+		return currentPod.Status.EphemeralContainerStatuses, nil
+	})
+}
+
+// CreateDebugPodCopy creates a copy of the pod with an additional debug container that shares the process namespace
+// with the other containers and waits until the debug container is running. The copy has no labels and owner references,
+// so that it is neither managed by a controller nor selected by services. The returned pod is nil if it couldn't be created
+func CreateDebugPodCopy(client *kubernetes.Clientset, pod *k8sv1.Pod, name, image string, command []string, maxWaiting time.Duration) (*k8sv1.Pod, error) {
+	shareProcessNamespace := true
+
+	debugPod := &k8sv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        pod.Name + "-" + name,
+			Namespace:   pod.Namespace,
+			Annotations: pod.Annotations,
+		},
+		Spec: *pod.Spec.DeepCopy(),
+	}
+
+	debugPod.Spec.NodeName = ""
+	debugPod.Spec.ShareProcessNamespace = &shareProcessNamespace
+	debugPod.Spec.Containers = append(debugPod.Spec.Containers, k8sv1.Container{
+		Name:                     name,
+		Image:                    image,
+		Command:                  command,
+		Stdin:                    true,
+		TTY:                      true,
+		TerminationMessagePolicy: k8sv1.TerminationMessageReadFile,
+		ImagePullPolicy:          k8sv1.PullIfNotPresent,
+	})
+
+	// The client returns an empty pod if the creation fails, which must not be returned to the caller
+	createdPod, err := client.Core().Pods(pod.Namespace).Create(debugPod)
+	if err != nil {
+		return nil, err
+	}
+
+	// The created pod is returned even if waiting fails, so that the caller can clean it up
+	return createdPod, waitForContainer(maxWaiting, createdPod.Namespace, createdPod.Name, name, func() ([]k8sv1.ContainerStatus, error) {
+		currentPod, err := client.Core().Pods(createdPod.Namespace).Get(createdPod.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+
+		return currentPod.Status.ContainerStatuses, nil
+	})
+}
+
+// DeletePod deletes the given pod immediately
+func DeletePod(client *kubernetes.Clientset, pod *k8sv1.Pod) error {
+	gracePeriod := int64(0)
+
+	return client.Core().Pods(pod.Namespace).Delete(pod.Name, &metav1.DeleteOptions{
+		GracePeriodSeconds: &gracePeriod,
+	})
+}
+
+func waitForContainer(maxWaiting time.Duration, namespace, pod, container string, getStatuses func() ([]k8sv1.ContainerStatus, error)) error {
+	waitingInterval := 1 * time.Second
+	for maxWaiting > 0 {
+		time.Sleep(waitingInterval)
+
+		statuses, err := getStatuses()
+		if err != nil {
+			return err
+		}
+
+		for _, status := range statuses {
+			if status.Name != container {
+				continue
+			}
+
+			if status.State.Running != nil {
+				return nil
+			}
+			if status.State.Terminated != nil {
+				return fmt.Errorf("Container %s in pod %s/%s terminated (Reason: %s)", container, namespace, pod, status.State.Terminated.Reason)
+			}
+			if status.State.Waiting != nil {
+				switch status.State.Waiting.Reason {
+				case "ErrImagePull", "ImagePullBackOff", "InvalidImageName", "CreateContainerConfigError", "CreateContainerError":
+					return fmt.Errorf("Container %s in pod %s/%s cannot start (Reason: %s)", container, namespace, pod, status.State.Waiting.Reason)
+				}
+			}
+		}
+
+		maxWaiting -= waitingInterval
+	}
+
+	return fmt.Errorf("Waiting for container %s in pod %s/%s timed out", container, namespace, pod)
+}
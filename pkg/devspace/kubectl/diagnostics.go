@@ -0,0 +1,202 @@
+package kubectl
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/covexo/devspace/pkg/util/log"
+	k8sv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Number of log lines that are printed for crashing containers in the diagnostic summary
+var summaryLogLines = int64(20)
+
+// Number of events per pod that are printed in the diagnostic summary
+const summaryEvents = 10
+
+// PodEventReporter prints warning events and container failure reasons of pods while waiting for them
+// and a diagnostic summary if waiting fails
+type PodEventReporter struct {
+	client *kubernetes.Clientset
+	log    log.Logger
+	since  time.Time
+
+	reportedEvents map[string]bool
+	reportedStates map[string]string
+}
+
+// NewPodEventReporter creates a new reporter that reports events that happen after its creation
+func NewPodEventReporter(client *kubernetes.Clientset, log log.Logger) *PodEventReporter {
+	return &PodEventReporter{
+		client: client,
+		log:    log,
+		since:  time.Now().Add(-time.Second),
+
+		reportedEvents: map[string]bool{},
+		reportedStates: map[string]string{},
+	}
+}
+
+// Report prints new warning events and container failure reasons of the given pods
+func (r *PodEventReporter) Report(pods []*k8sv1.Pod) {
+	for _, pod := range pods {
+		for _, status := range getAllContainerStatuses(pod) {
+			key := pod.Namespace + "/" + pod.Name + "/" + status.Name
+			reason := getContainerFailureReason(&status)
+
+			if reason != "" && r.reportedStates[key] != reason {
+				r.log.Warnf("Pod %s: Container %s %s", pod.Name, status.Name, reason)
+			}
+
+			r.reportedStates[key] = reason
+		}
+
+		events, err := getPodEvents(r.client, pod)
+		if err != nil {
+			continue
+		}
+
+		for _, event := range events {
+			// The count is part of the key, so that repeated events are reported again
+			key := fmt.Sprintf("%s/%d", event.UID, event.Count)
+			if event.Type != k8sv1.EventTypeWarning || r.reportedEvents[key] || getEventTime(&event).Before(r.since) {
+				continue
+			}
+
+			r.reportedEvents[key] = true
+			r.log.Warnf("Pod %s: %s: %s", pod.Name, event.Reason, strings.TrimSpace(event.Message))
+		}
+	}
+}
+
+// PrintSummary prints the status, the recent events and the last logs of crashing containers of the given pods
+func (r *PodEventReporter) PrintSummary(labelSelector, namespace string, pods []*k8sv1.Pod) {
+	if len(pods) == 0 {
+		r.log.Warnf("No pods with selector %s found in namespace %s. Check if the deployment was successful and the label selector is correct", labelSelector, namespace)
+		return
+	}
+
+	summary := "Diagnostic summary:\n"
+	for _, pod := range pods {
+		summary += fmt.Sprintf("\nPod %s (Status: %s, Age: %s)\n", pod.Name, GetPodStatus(pod), duration.HumanDuration(time.Since(pod.CreationTimestamp.Time)))
+
+		for _, condition := range pod.Status.Conditions {
+			if condition.Status != k8sv1.ConditionTrue && condition.Message != "" {
+				summary += fmt.Sprintf("  Condition %s: %s\n", condition.Type, condition.Message)
+			}
+		}
+
+		crashingContainers := []k8sv1.ContainerStatus{}
+		for _, status := range getAllContainerStatuses(pod) {
+			reason := getContainerFailureReason(&status)
+			if reason == "" {
+				if status.Ready {
+					reason = "is ready"
+				} else {
+					reason = "is not ready"
+				}
+			}
+
+			summary += fmt.Sprintf("  Container %s %s (Restarts: %d)\n", status.Name, reason, status.RestartCount)
+
+			if status.RestartCount > 0 || status.State.Terminated != nil {
+				crashingContainers = append(crashingContainers, status)
+			}
+		}
+
+		events, err := getPodEvents(r.client, pod)
+		if err == nil && len(events) > 0 {
+			if len(events) > summaryEvents {
+				events = events[len(events)-summaryEvents:]
+			}
+
+			summary += "  Events:\n"
+			for _, event := range events {
+				summary += fmt.Sprintf("    %-8s %-20s %-5s ago  %s\n", event.Type, event.Reason, duration.HumanDuration(time.Since(getEventTime(&event))), strings.TrimSpace(event.Message))
+			}
+		}
+
+		for _, status := range crashingContainers {
+			// Show the logs of the crashed instance if the container was restarted and isn't running yet
+			previous := status.State.Running == nil && status.RestartCount > 0
+
+			logs, err := Logs(r.client, pod.Namespace, pod.Name, status.Name, previous, &summaryLogLines)
+			if err != nil || strings.TrimSpace(logs) == "" {
+				continue
+			}
+
+			summary += fmt.Sprintf("  Last logs of container %s:\n", status.Name)
+			for _, line := range strings.Split(strings.TrimRight(logs, "\n"), "\n") {
+				summary += "    " + line + "\n"
+			}
+		}
+	}
+
+	r.log.Warn(strings.TrimRight(summary, "\n"))
+}
+
+func getAllContainerStatuses(pod *k8sv1.Pod) []k8sv1.ContainerStatus {
+	statuses := make([]k8sv1.ContainerStatus, 0, len(pod.Status.InitContainerStatuses)+len(pod.Status.ContainerStatuses))
+	statuses = append(statuses, pod.Status.InitContainerStatuses...)
+
+	return append(statuses, pod.Status.ContainerStatuses...)
+}
+
+// getContainerFailureReason returns why the container doesn't start or an empty string if nothing is wrong
+func getContainerFailureReason(status *k8sv1.ContainerStatus) string {
+	if status.State.Waiting != nil {
+		switch status.State.Waiting.Reason {
+		case "", "ContainerCreating", "PodInitializing":
+			return ""
+		}
+
+		reason := "is waiting: " + status.State.Waiting.Reason
+		if status.State.Waiting.Message != "" {
+			reason += " (" + strings.TrimSpace(status.State.Waiting.Message) + ")"
+		}
+		if status.LastTerminationState.Terminated != nil {
+			reason += fmt.Sprintf(", last exit: %s with exit code %d", status.LastTerminationState.Terminated.Reason, status.LastTerminationState.Terminated.ExitCode)
+		}
+
+		return reason
+	}
+
+	if status.State.Terminated != nil && (status.State.Terminated.ExitCode != 0 || status.State.Terminated.Signal != 0) {
+		return fmt.Sprintf("terminated: %s with exit code %d", status.State.Terminated.Reason, status.State.Terminated.ExitCode)
+	}
+
+	return ""
+}
+
+// getPodEvents returns the events of the pod sorted by time (oldest first)
+func getPodEvents(client *kubernetes.Clientset, pod *k8sv1.Pod) ([]k8sv1.Event, error) {
+	eventList, err := client.Core().Events(pod.Namespace).List(metav1.ListOptions{
+		FieldSelector: "involvedObject.kind=Pod,involvedObject.name=" + pod.Name,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	events := eventList.Items
+	sort.SliceStable(events, func(i, j int) bool {
+		return getEventTime(&events[i]).Before(getEventTime(&events[j]))
+	})
+
+	return events, nil
+}
+
+func getEventTime(event *k8sv1.Event) time.Time {
+	if event.LastTimestamp.IsZero() == false {
+		return event.LastTimestamp.Time
+	}
+	if event.EventTime.IsZero() == false {
+		return event.EventTime.Time
+	}
+
+	return event.FirstTimestamp.Time
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"os"
 
+	"github.com/covexo/devspace/pkg/util/asciicast"
 	"github.com/covexo/devspace/pkg/util/terminal"
 	k8sv1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes"
@@ -16,8 +17,8 @@ import (
 	"k8s.io/kubernetes/pkg/kubectl/util/term"
 )
 
-// ExecStreamWithTransport executes a kubectl exec with given transport round tripper and upgrader
-func ExecStreamWithTransport(transport http.RoundTripper, upgrader spdy.Upgrader, client *kubernetes.Clientset, pod *k8sv1.Pod, container string, command []string, tty bool, stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
+// ExecStreamWithTransport executes a kubectl exec with given transport round tripper and upgrader. If recorder is not nil the session is recorded
+func ExecStreamWithTransport(transport http.RoundTripper, upgrader spdy.Upgrader, client *kubernetes.Clientset, pod *k8sv1.Pod, container string, command []string, tty bool, stdin io.Reader, stdout io.Writer, stderr io.Writer, recorder *asciicast.Recorder) error {
 	var t term.TTY
 	var sizeQueue remotecommand.TerminalSizeQueue
 	var streamOptions remotecommand.StreamOptions
@@ -34,8 +35,30 @@ func ExecStreamWithTransport(transport http.RoundTripper, upgrader spdy.Upgrader
 		if t.Raw {
 			// this call spawns a goroutine to monitor/update the terminal size
 			sizeQueue = t.MonitorSize(t.GetSize())
+
+			if recorder != nil {
+				sizeQueue = &recordingSizeQueue{
+					queue:    sizeQueue,
+					recorder: recorder,
+				}
+			}
+		}
+	}
+
+	// Wrap the streams after the tty setup, otherwise the terminal detection wouldn't work anymore
+	if recorder != nil {
+		if stdin != nil {
+			stdin = recorder.WrapInput(stdin)
 		}
+		if stdout != nil {
+			stdout = recorder.WrapOutput(stdout)
+		}
+		if stderr != nil {
+			stderr = recorder.WrapOutput(stderr)
+		}
+	}
 
+	if tty {
 		streamOptions = remotecommand.StreamOptions{
 			Stdin:             stdin,
 			Stdout:            stdout,
@@ -82,7 +105,23 @@ func ExecStream(client *kubernetes.Clientset, pod *k8sv1.Pod, container string,
 		return err
 	}
 
-	return ExecStreamWithTransport(wrapper, upgradeRoundTripper, client, pod, container, command, tty, stdin, stdout, stderr)
+	return ExecStreamWithTransport(wrapper, upgradeRoundTripper, client, pod, container, command, tty, stdin, stdout, stderr, nil)
+}
+
+// recordingSizeQueue records every terminal resize before passing it on
+type recordingSizeQueue struct {
+	queue    remotecommand.TerminalSizeQueue
+	recorder *asciicast.Recorder
+}
+
+// Next implements the remotecommand.TerminalSizeQueue interface
+func (r *recordingSizeQueue) Next() *remotecommand.TerminalSize {
+	size := r.queue.Next()
+	if size != nil {
+		r.recorder.Resize(size.Width, size.Height)
+	}
+
+	return size
 }
 
 // ExecBuffered executes a command for kubernetes and returns the output and error buffers
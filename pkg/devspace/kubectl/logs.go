@@ -0,0 +1,39 @@
+package kubectl
+
+import (
+	"io"
+	"io/ioutil"
+
+	k8sv1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// LogStream opens a log stream for the given container with the specified options
+func LogStream(client *kubernetes.Clientset, namespace, pod, container string, options *k8sv1.PodLogOptions) (io.ReadCloser, error) {
+	if options == nil {
+		options = &k8sv1.PodLogOptions{}
+	}
+
+	options.Container = container
+
+	return client.Core().Pods(namespace).GetLogs(pod, options).Stream()
+}
+
+// Logs returns the last logs of a container (if previous is true the logs of the last terminated container instance are returned)
+func Logs(client *kubernetes.Clientset, namespace, pod, container string, previous bool, tail *int64) (string, error) {
+	reader, err := LogStream(client, namespace, pod, container, &k8sv1.PodLogOptions{
+		Previous:  previous,
+		TailLines: tail,
+	})
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+
+	logs, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return "", err
+	}
+
+	return string(logs), nil
+}
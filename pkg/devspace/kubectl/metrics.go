@@ -0,0 +1,192 @@
+package kubectl
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/covexo/devspace/pkg/devspace/config/configutil"
+	k8sv1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// metricsAPIPath is the path of the pod metrics that are served by the metrics-server
+const metricsAPIPath = "/apis/metrics.k8s.io/v1beta1/namespaces/%s/pods"
+
+// podMetricsList is the pod metrics list of the metrics.k8s.io api
+type podMetricsList struct {
+	Items []podMetrics `json:"items"`
+}
+
+type podMetrics struct {
+	Metadata   metav1.ObjectMeta  `json:"metadata"`
+	Containers []containerMetrics `json:"containers"`
+}
+
+type containerMetrics struct {
+	Name  string             `json:"name"`
+	Usage k8sv1.ResourceList `json:"usage"`
+}
+
+// ContainerUsage holds the resource usage of a container compared to its requests and limits
+type ContainerUsage struct {
+	Namespace string
+	Pod       string
+	Container string
+
+	// CPU and Memory are nil if no metrics are available for the container
+	CPU    *resource.Quantity
+	Memory *resource.Quantity
+
+	Requests k8sv1.ResourceList
+	Limits   k8sv1.ResourceList
+
+	Restarts int32
+
+	// LastTermination is the reason why the container was terminated last (e.g. OOMKilled)
+	LastTermination string
+}
+
+// GetContainerUsage returns the resource usage of the containers of all pods that match the label selector. The returned
+// bool is false if the metrics api is not available (e.g. because metrics-server is not installed), in this case the usage
+// only contains requests, limits and restarts
+func GetContainerUsage(client *kubernetes.Clientset, labelSelector, namespace string) ([]*ContainerUsage, bool, error) {
+	if namespace == "" {
+		defaultNamespace, err := configutil.GetDefaultNamespace(configutil.GetConfig())
+		if err != nil {
+			return nil, false, err
+		}
+
+		namespace = defaultNamespace
+	}
+
+	podList, err := client.Core().Pods(namespace).List(metav1.ListOptions{
+		LabelSelector: labelSelector,
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	metrics, metricsAvailable, err := getPodMetrics(client, labelSelector, namespace)
+	if err != nil {
+		return nil, false, err
+	}
+
+	usages := []*ContainerUsage{}
+	for _, pod := range podList.Items {
+		statuses := map[string]k8sv1.ContainerStatus{}
+		for _, status := range pod.Status.ContainerStatuses {
+			statuses[status.Name] = status
+		}
+
+		for _, container := range pod.Spec.Containers {
+			usage := &ContainerUsage{
+				Namespace: pod.Namespace,
+				Pod:       pod.Name,
+				Container: container.Name,
+				Requests:  container.Resources.Requests,
+				Limits:    container.Resources.Limits,
+			}
+
+			if status, ok := statuses[container.Name]; ok {
+				usage.Restarts = status.RestartCount
+				if status.LastTerminationState.Terminated != nil {
+					usage.LastTermination = status.LastTerminationState.Terminated.Reason
+				}
+			}
+
+			if containerMetrics, ok := metrics[pod.Name+"/"+container.Name]; ok {
+				if cpu, ok := containerMetrics[k8sv1.ResourceCPU]; ok {
+					usage.CPU = &cpu
+				}
+				if memory, ok := containerMetrics[k8sv1.ResourceMemory]; ok {
+					usage.Memory = &memory
+				}
+			}
+
+			usages = append(usages, usage)
+		}
+	}
+
+	return usages, metricsAvailable, nil
+}
+
+// getPodMetrics returns the usage of the containers by pod/container name. Returns false if the metrics api isn't available
+func getPodMetrics(client *kubernetes.Clientset, labelSelector, namespace string) (map[string]k8sv1.ResourceList, bool, error) {
+	request := client.Core().RESTClient().Get().AbsPath(fmt.Sprintf(metricsAPIPath, namespace))
+	if labelSelector != "" {
+		request = request.Param("labelSelector", labelSelector)
+	}
+
+	out, err := request.Do().Raw()
+	if err != nil {
+		// The api group doesn't exist or the metrics-server isn't ready
+		if kerrors.IsNotFound(err) || kerrors.IsServiceUnavailable(err) || kerrors.IsMethodNotSupported(err) {
+			return nil, false, nil
+		}
+
+		return nil, false, fmt.Errorf("Error retrieving pod metrics: %v", err)
+	}
+
+	list := &podMetricsList{}
+	err = json.Unmarshal(out, list)
+	if err != nil {
+		return nil, false, fmt.Errorf("Error parsing pod metrics: %v", err)
+	}
+
+	metrics := map[string]k8sv1.ResourceList{}
+	for _, pod := range list.Items {
+		for _, container := range pod.Containers {
+			metrics[pod.Metadata.Name+"/"+container.Name] = container.Usage
+		}
+	}
+
+	return metrics, true, nil
+}
+
+// FormatUsage formats the usage of a resource with its percentage of the limit or, if there is no limit, of the request
+func FormatUsage(usage *resource.Quantity, requests, limits k8sv1.ResourceList, resourceName k8sv1.ResourceName) string {
+	if usage == nil {
+		return "n/a"
+	}
+
+	formatted := FormatQuantity(usage, resourceName)
+
+	if limit, ok := limits[resourceName]; ok && limit.IsZero() == false {
+		return fmt.Sprintf("%s (%d%% of limit)", formatted, getPercentage(usage, &limit, resourceName))
+	}
+	if request, ok := requests[resourceName]; ok && request.IsZero() == false {
+		return fmt.Sprintf("%s (%d%% of request)", formatted, getPercentage(usage, &request, resourceName))
+	}
+
+	return formatted
+}
+
+// FormatResource formats a request or limit
+func FormatResource(resources k8sv1.ResourceList, resourceName k8sv1.ResourceName) string {
+	quantity, ok := resources[resourceName]
+	if !ok {
+		return "-"
+	}
+
+	return FormatQuantity(&quantity, resourceName)
+}
+
+// FormatQuantity formats cpu in millicores and memory in mebibytes like kubectl top
+func FormatQuantity(quantity *resource.Quantity, resourceName k8sv1.ResourceName) string {
+	if resourceName == k8sv1.ResourceCPU {
+		return fmt.Sprintf("%dm", quantity.MilliValue())
+	}
+
+	return fmt.Sprintf("%dMi", quantity.Value()/(1024*1024))
+}
+
+func getPercentage(usage, total *resource.Quantity, resourceName k8sv1.ResourceName) int64 {
+	if resourceName == k8sv1.ResourceCPU {
+		return usage.MilliValue() * 100 / total.MilliValue()
+	}
+
+	return usage.Value() * 100 / total.Value()
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/covexo/devspace/pkg/devspace/config/configutil"
+	"github.com/covexo/devspace/pkg/devspace/sync"
+	"github.com/covexo/devspace/pkg/util/log"
+	units "github.com/docker/go-units"
+	k8sv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	copyServicePrefix = "service:"
+	copyPodPrefix     = "pod:"
+)
+
+// remotePath is a parsed container path of devspace cp
+type remotePath struct {
+	// Either service or pod is set. If both are empty, the terminal service is used
+	Service string
+	Pod     string
+	Path    string
+}
+
+// parseRemotePath parses paths in the form service:[name:]path or pod:name:path. Returns nil if the path is a local path
+func parseRemotePath(value string) (*remotePath, error) {
+	if strings.HasPrefix(value, copyServicePrefix) {
+		value = value[len(copyServicePrefix):]
+
+		// A colon separates the service name from the path, otherwise the default service is used
+		splitted := strings.SplitN(value, ":", 2)
+		if len(splitted) == 2 {
+			return &remotePath{Service: splitted[0], Path: splitted[1]}, nil
+		}
+
+		return &remotePath{Path: value}, nil
+	}
+
+	if strings.HasPrefix(value, copyPodPrefix) {
+		value = value[len(copyPodPrefix):]
+
+		splitted := strings.SplitN(value, ":", 2)
+		if len(splitted) != 2 || splitted[0] == "" {
+			return nil, fmt.Errorf("Invalid path %s%s: expected pod:name:path", copyPodPrefix, value)
+		}
+
+		return &remotePath{Pod: splitted[0], Path: splitted[1]}, nil
+	}
+
+	return nil, nil
+}
+
+// Copy copies files and directories between the local filesystem and a container. Exactly one of source and destination
+// has to be a container path with a service: or pod: prefix
+func Copy(client *kubernetes.Clientset, source, destination, containerNameOverride, namespaceOverride string, excludePaths []string, pick bool, log log.Logger) error {
+	remoteSource, err := parseRemotePath(source)
+	if err != nil {
+		return err
+	}
+
+	remoteDestination, err := parseRemotePath(destination)
+	if err != nil {
+		return err
+	}
+
+	if (remoteSource == nil) == (remoteDestination == nil) {
+		return errors.New("Either the source or the destination has to be a container path (e.g. service:/app/file, service:my-service:/app/file or pod:my-pod:/app/file)")
+	}
+
+	remote := remoteSource
+	if remote == nil {
+		remote = remoteDestination
+	}
+	if remote.Path == "" {
+		return errors.New("Container path must not be empty")
+	}
+
+	pod, containerName, err := getCopyPodAndContainer(client, remote, containerNameOverride, namespaceOverride, pick, log)
+	if err != nil {
+		return err
+	}
+
+	// Show the transferred bytes, but don't restart the wait message for every chunk
+	lastUpdate := time.Time{}
+	progress := func(message string) sync.ProgressFunc {
+		return func(transferred int64) {
+			if time.Since(lastUpdate) > time.Millisecond*500 {
+				lastUpdate = time.Now()
+				log.StartWait(fmt.Sprintf("%s (%s)", message, units.HumanSize(float64(transferred))))
+			}
+		}
+	}
+
+	if remoteSource != nil {
+		message := fmt.Sprintf("Copying %s from %s/%s to %s", remote.Path, pod.Name, containerName, destination)
+
+		log.StartWait(message)
+		err = sync.Download(client, pod, containerName, remote.Path, destination, excludePaths, progress(message))
+		log.StopWait()
+		if err != nil {
+			return fmt.Errorf("Error copying %s from %s/%s: %v", remote.Path, pod.Name, containerName, err)
+		}
+
+		log.Donef("Copied %s from %s/%s to %s", remote.Path, pod.Name, containerName, destination)
+		return nil
+	}
+
+	message := fmt.Sprintf("Copying %s to %s in %s/%s", source, remote.Path, pod.Name, containerName)
+
+	log.StartWait(message)
+	err = sync.Upload(client, pod, containerName, source, remote.Path, excludePaths, progress(message))
+	log.StopWait()
+	if err != nil {
+		return fmt.Errorf("Error copying %s to %s/%s: %v", source, pod.Name, containerName, err)
+	}
+
+	log.Donef("Copied %s to %s in %s/%s", source, remote.Path, pod.Name, containerName)
+	return nil
+}
+
+func getCopyPodAndContainer(client *kubernetes.Clientset, remote *remotePath, containerNameOverride, namespaceOverride string, pick bool, log log.Logger) (*k8sv1.Pod, string, error) {
+	config := configutil.GetConfig()
+
+	if remote.Pod != "" {
+		namespace := namespaceOverride
+		if namespace == "" {
+			defaultNamespace, err := configutil.GetDefaultNamespace(config)
+			if err != nil {
+				return nil, "", err
+			}
+
+			namespace = defaultNamespace
+		}
+
+		pod, err := client.Core().Pods(namespace).Get(remote.Pod, metav1.GetOptions{})
+		if err != nil {
+			return nil, "", fmt.Errorf("Error retrieving pod %s/%s: %v", namespace, remote.Pod, err)
+		}
+
+		return pod, selectContainer(pod, containerNameOverride, pick, log), nil
+	}
+
+	service, namespace, labelSelector, err := getServiceNamespaceLabelSelector(remote.Service, "", namespaceOverride)
+	if err != nil {
+		return nil, "", err
+	}
+
+	log.StartWait("Copy: Waiting for pods...")
+	pod, err := selectPod(client, labelSelector, namespace, time.Second*120, pick, log)
+	log.StopWait()
+	if err != nil {
+		return nil, "", fmt.Errorf("Error copying files: Cannot find running pod: %v", err)
+	}
+
+	// Get container name
+	containerName := containerNameOverride
+	if containerName == "" {
+		if service != nil && service.ContainerName != nil {
+			containerName = *service.ContainerName
+		} else if config.DevSpace.Terminal != nil && config.DevSpace.Terminal.ContainerName != nil {
+			containerName = *config.DevSpace.Terminal.ContainerName
+		}
+	}
+
+	return pod, selectContainer(pod, containerName, pick, log), nil
+}
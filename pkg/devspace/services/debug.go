@@ -0,0 +1,108 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/covexo/devspace/pkg/devspace/config/configutil"
+	"github.com/covexo/devspace/pkg/devspace/kubectl"
+	"github.com/covexo/devspace/pkg/util/log"
+	"k8s.io/client-go/kubernetes"
+	kubectlExec "k8s.io/client-go/util/exec"
+)
+
+// DefaultDebugImage is the image used for debug containers if no image is specified
+const DefaultDebugImage = "busybox"
+
+// StartDebugTerminal opens a terminal in a debug container next to the selected container. This also works for images without a shell.
+// If the cluster supports ephemeral containers, an ephemeral container that shares the process namespace of the selected container
+// is added to the pod. Otherwise a copy of the pod with an additional debug container is created and deleted after the session
+func StartDebugTerminal(client *kubernetes.Clientset, serviceNameOverride, containerNameOverride, labelSelectorOverride, namespaceOverride, image string, args []string, pick bool, log log.Logger) error {
+	config := configutil.GetConfig()
+
+	command := args
+	if len(command) == 0 {
+		command = []string{"sh"}
+	}
+	if image == "" {
+		image = DefaultDebugImage
+	}
+
+	service, namespace, labelSelector, err := getServiceNamespaceLabelSelector(serviceNameOverride, labelSelectorOverride, namespaceOverride)
+	if err != nil {
+		return err
+	}
+
+	log.StartWait("Debug: Waiting for pods...")
+	pod, err := selectPod(client, labelSelector, namespace, time.Second*120, pick, log)
+	log.StopWait()
+	if err != nil {
+		return fmt.Errorf("Error starting debug container: Cannot find running pod: %v", err)
+	}
+
+	// Get container name
+	containerName := containerNameOverride
+	if containerName == "" {
+		if service != nil && service.ContainerName != nil {
+			containerName = *service.ContainerName
+		} else if config.DevSpace.Terminal.ContainerName != nil {
+			containerName = *config.DevSpace.Terminal.ContainerName
+		}
+	}
+
+	containerName = selectContainer(pod, containerName, pick, log)
+
+	debugContainerName, err := kubectl.GetDebugContainerName()
+	if err != nil {
+		return err
+	}
+
+	log.StartWait("Debug: Starting ephemeral container " + debugContainerName + " in pod " + pod.Name)
+	err = kubectl.AddEphemeralContainer(client, pod, debugContainerName, image, command, containerName, time.Second*120)
+	log.StopWait()
+	if err == kubectl.ErrEphemeralContainersNotSupported {
+		log.Infof("Debug: Cluster doesn't support ephemeral containers, creating a copy of pod %s with a debug container instead", pod.Name)
+
+		log.StartWait("Debug: Starting pod " + pod.Name + "-" + debugContainerName)
+		debugPod, err := kubectl.CreateDebugPodCopy(client, pod, debugContainerName, image, command, time.Second*120)
+		log.StopWait()
+
+		// The copy is only deleted if it was created, waiting for the debug container may still have failed
+		if debugPod != nil {
+			defer func() {
+				err := kubectl.DeletePod(client, debugPod)
+				if err != nil {
+					log.Warnf("Error deleting debug pod %s: %v", debugPod.Name, err)
+					return
+				}
+
+				log.Donef("Deleted debug pod %s", debugPod.Name)
+			}()
+		}
+		if err != nil {
+			return fmt.Errorf("Error starting debug pod: %v", err)
+		}
+
+		pod = debugPod
+	} else if err != nil {
+		return fmt.Errorf("Error starting ephemeral container: %v", err)
+	}
+
+	wrapper, upgradeRoundTripper, err := getUpgraderWrapper()
+	if err != nil {
+		return err
+	}
+	defer upgradeRoundTripper.Close()
+
+	log.Infof("Debug: Attaching to container %s in pod %s. If you don't see a command prompt, try pressing enter", debugContainerName, pod.Name)
+
+	err = kubectl.AttachStreamWithTransport(wrapper, upgradeRoundTripper, client, pod, debugContainerName, true, os.Stdin, os.Stdout, os.Stderr)
+	if err != nil {
+		if _, ok := err.(kubectlExec.CodeExitError); ok == false {
+			return fmt.Errorf("Unable to attach to debug container: %v", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,449 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/covexo/devspace/pkg/devspace/config/configutil"
+	"github.com/covexo/devspace/pkg/devspace/config/generated"
+	"github.com/covexo/devspace/pkg/devspace/config/v1"
+	"github.com/covexo/devspace/pkg/devspace/registry"
+	"github.com/covexo/devspace/pkg/util/log"
+	appsv1 "k8s.io/api/apps/v1"
+	k8sv1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// OriginalSpecAnnotation holds the original spec of a deployment or statefulset while it is in dev mode
+const OriginalSpecAnnotation = "devspace.covexo.com/original-spec"
+
+// DevModeAnnotation is added to the pod template of a deployment or statefulset in dev mode
+const DevModeAnnotation = "devspace.covexo.com/dev-mode"
+
+// Supported kinds of dev mode workloads
+const (
+	DevModeKindDeployment  = "deployment"
+	DevModeKindStatefulSet = "statefulset"
+)
+
+// devModeVolumeName is the name of the emptyDir volume that is mounted at the volume path
+const devModeVolumeName = "devspace-dev-mode"
+
+// devModeOptions holds the resolved dev mode config of a service
+type devModeOptions struct {
+	ContainerName string
+	Image         string
+	Command       []string
+	Args          []string
+	VolumePath    string
+}
+
+// StartDevMode patches the deployments and statefulsets of all services with an enabled dev mode. The original
+// specs are saved in an annotation, so that they can be restored with StopDevMode. If a workload cannot be patched,
+// the workloads that were already patched are restored
+func StartDevMode(client *kubernetes.Clientset, generatedConfig *generated.Config, log log.Logger) error {
+	started := []*v1.ServiceConfig{}
+
+	for _, service := range getDevModeServices() {
+		kind, namespace, err := getDevModeKindAndNamespace(service)
+		if err != nil {
+			stopDevModeServices(client, started, log)
+			return err
+		}
+
+		options := getDevModeOptions(service, generatedConfig)
+		name := *service.DevMode.Name
+
+		log.StartWait(fmt.Sprintf("Starting dev mode for %s %s/%s", kind, namespace, name))
+		err = startDevMode(client, kind, namespace, name, options)
+		log.StopWait()
+		if err != nil {
+			stopDevModeServices(client, started, log)
+			return fmt.Errorf("Error starting dev mode for service %s: %v", *service.Name, err)
+		}
+
+		started = append(started, service)
+		log.Donef("Started dev mode for service %s (%s %s/%s)", *service.Name, kind, namespace, name)
+	}
+
+	return nil
+}
+
+// StopDevMode restores the original specs of the deployments and statefulsets of all services with an enabled dev mode.
+// Errors are printed as warnings, so that the remaining workloads are still restored
+func StopDevMode(client *kubernetes.Clientset, log log.Logger) {
+	stopDevModeServices(client, getDevModeServices(), log)
+}
+
+func stopDevModeServices(client *kubernetes.Clientset, services []*v1.ServiceConfig, log log.Logger) {
+	for _, service := range services {
+		kind, namespace, err := getDevModeKindAndNamespace(service)
+		if err != nil {
+			log.Warn(err)
+			continue
+		}
+
+		name := *service.DevMode.Name
+
+		log.StartWait(fmt.Sprintf("Stopping dev mode for %s %s/%s", kind, namespace, name))
+		restored, err := stopDevMode(client, kind, namespace, name)
+		log.StopWait()
+		if err != nil {
+			log.Warnf("Error stopping dev mode for service %s: %v", *service.Name, err)
+			continue
+		}
+
+		if restored {
+			log.Donef("Restored original %s %s/%s of service %s", kind, namespace, name, *service.Name)
+		}
+	}
+}
+
+// HasDevMode returns true if at least one service has an enabled dev mode
+func HasDevMode() bool {
+	return len(getDevModeServices()) > 0
+}
+
+func getDevModeServices() []*v1.ServiceConfig {
+	config := configutil.GetConfig()
+	services := []*v1.ServiceConfig{}
+
+	if config.DevSpace == nil || config.DevSpace.Services == nil {
+		return services
+	}
+
+	for _, service := range *config.DevSpace.Services {
+		if service.DevMode == nil || (service.DevMode.Disabled != nil && *service.DevMode.Disabled == true) {
+			continue
+		}
+
+		services = append(services, service)
+	}
+
+	return services
+}
+
+func getDevModeKindAndNamespace(service *v1.ServiceConfig) (string, string, error) {
+	if service.DevMode.Name == nil || *service.DevMode.Name == "" {
+		return "", "", fmt.Errorf("Error in dev mode of service %s: name is required", *service.Name)
+	}
+
+	kind := DevModeKindDeployment
+	if service.DevMode.Kind != nil {
+		kind = strings.ToLower(*service.DevMode.Kind)
+		if kind != DevModeKindDeployment && kind != DevModeKindStatefulSet {
+			return "", "", fmt.Errorf("Error in dev mode of service %s: unsupported kind %s (supported: Deployment, StatefulSet)", *service.Name, *service.DevMode.Kind)
+		}
+	}
+
+	namespace := ""
+	if service.Namespace != nil && *service.Namespace != "" {
+		namespace = *service.Namespace
+	} else {
+		defaultNamespace, err := configutil.GetDefaultNamespace(configutil.GetConfig())
+		if err != nil {
+			return "", "", err
+		}
+
+		namespace = defaultNamespace
+	}
+
+	return kind, namespace, nil
+}
+
+// getDevModeOptions resolves the dev mode config. The image is either the name of an image in the images config or an image reference
+func getDevModeOptions(service *v1.ServiceConfig, generatedConfig *generated.Config) *devModeOptions {
+	config := configutil.GetConfig()
+	options := &devModeOptions{}
+
+	if service.ContainerName != nil {
+		options.ContainerName = *service.ContainerName
+	}
+
+	if service.DevMode.Image != nil && *service.DevMode.Image != "" {
+		options.Image = *service.DevMode.Image
+
+		if config.Images != nil {
+			if imageConfig, ok := (*config.Images)[*service.DevMode.Image]; ok {
+				options.Image = registry.GetImageURL(generatedConfig, imageConfig, true)
+			}
+		}
+	}
+
+	if service.DevMode.Command != nil {
+		options.Command = []string{}
+		for _, value := range *service.DevMode.Command {
+			options.Command = append(options.Command, *value)
+		}
+	}
+
+	if service.DevMode.Args != nil {
+		options.Args = []string{}
+		for _, value := range *service.DevMode.Args {
+			options.Args = append(options.Args, *value)
+		}
+	}
+
+	if service.DevMode.VolumePath != nil {
+		options.VolumePath = *service.DevMode.VolumePath
+	}
+
+	return options
+}
+
+// startDevMode saves the original spec of the workload in an annotation and replaces it with the dev spec
+func startDevMode(client *kubernetes.Clientset, kind, namespace, name string, options *devModeOptions) error {
+	switch kind {
+	case DevModeKindDeployment:
+		deployment, err := client.AppsV1().Deployments(namespace).Get(name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		err = patchDeployment(deployment, options)
+		if err != nil {
+			return err
+		}
+
+		_, err = client.AppsV1().Deployments(namespace).Update(deployment)
+		return err
+	case DevModeKindStatefulSet:
+		statefulSet, err := client.AppsV1().StatefulSets(namespace).Get(name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		err = patchStatefulSet(statefulSet, options)
+		if err != nil {
+			return err
+		}
+
+		_, err = client.AppsV1().StatefulSets(namespace).Update(statefulSet)
+		return err
+	}
+
+	return fmt.Errorf("Unsupported kind %s", kind)
+}
+
+// patchDeployment replaces the spec of the deployment with the dev spec, which runs a single replica
+func patchDeployment(deployment *appsv1.Deployment, options *devModeOptions) error {
+	replicas := int32(1)
+	spec := &appsv1.DeploymentSpec{}
+
+	err := loadOrSaveOriginalSpec(&deployment.ObjectMeta, &deployment.Spec, spec)
+	if err != nil {
+		return err
+	}
+
+	spec.Replicas = &replicas
+	err = applyDevModeTemplate(&spec.Template, options)
+	if err != nil {
+		return err
+	}
+
+	deployment.Spec = *spec
+	return nil
+}
+
+// patchStatefulSet replaces the spec of the statefulset with the dev spec, which runs a single replica
+func patchStatefulSet(statefulSet *appsv1.StatefulSet, options *devModeOptions) error {
+	replicas := int32(1)
+	spec := &appsv1.StatefulSetSpec{}
+
+	err := loadOrSaveOriginalSpec(&statefulSet.ObjectMeta, &statefulSet.Spec, spec)
+	if err != nil {
+		return err
+	}
+
+	spec.Replicas = &replicas
+	err = applyDevModeTemplate(&spec.Template, options)
+	if err != nil {
+		return err
+	}
+
+	statefulSet.Spec = *spec
+	return nil
+}
+
+// stopDevMode restores the original spec of the workload and removes the annotation. Returns false if the workload
+// is not in dev mode
+func stopDevMode(client *kubernetes.Clientset, kind, namespace, name string) (bool, error) {
+	switch kind {
+	case DevModeKindDeployment:
+		deployment, err := client.AppsV1().Deployments(namespace).Get(name, metav1.GetOptions{})
+		if err != nil {
+			if kerrors.IsNotFound(err) {
+				return false, nil
+			}
+
+			return false, err
+		}
+
+		found, err := restoreDeployment(deployment)
+		if err != nil || found == false {
+			return false, err
+		}
+
+		_, err = client.AppsV1().Deployments(namespace).Update(deployment)
+		return err == nil, err
+	case DevModeKindStatefulSet:
+		statefulSet, err := client.AppsV1().StatefulSets(namespace).Get(name, metav1.GetOptions{})
+		if err != nil {
+			if kerrors.IsNotFound(err) {
+				return false, nil
+			}
+
+			return false, err
+		}
+
+		found, err := restoreStatefulSet(statefulSet)
+		if err != nil || found == false {
+			return false, err
+		}
+
+		_, err = client.AppsV1().StatefulSets(namespace).Update(statefulSet)
+		return err == nil, err
+	}
+
+	return false, fmt.Errorf("Unsupported kind %s", kind)
+}
+
+// restoreDeployment restores the original spec of the deployment. Returns false if the deployment is not in dev mode
+func restoreDeployment(deployment *appsv1.Deployment) (bool, error) {
+	spec := &appsv1.DeploymentSpec{}
+
+	found, err := restoreOriginalSpec(&deployment.ObjectMeta, spec)
+	if err != nil || found == false {
+		return false, err
+	}
+
+	deployment.Spec = *spec
+	return true, nil
+}
+
+// restoreStatefulSet restores the original spec of the statefulset. Returns false if the statefulset is not in dev mode
+func restoreStatefulSet(statefulSet *appsv1.StatefulSet) (bool, error) {
+	spec := &appsv1.StatefulSetSpec{}
+
+	found, err := restoreOriginalSpec(&statefulSet.ObjectMeta, spec)
+	if err != nil || found == false {
+		return false, err
+	}
+
+	statefulSet.Spec = *spec
+	return true, nil
+}
+
+// loadOrSaveOriginalSpec saves the current spec in the original spec annotation and copies it into original. If the
+// annotation already exists, because the dev mode wasn't stopped before, the saved spec is copied into original instead
+func loadOrSaveOriginalSpec(meta *metav1.ObjectMeta, current, original interface{}) error {
+	if saved, ok := meta.Annotations[OriginalSpecAnnotation]; ok {
+		err := json.Unmarshal([]byte(saved), original)
+		if err != nil {
+			return fmt.Errorf("Error parsing annotation %s: %v", OriginalSpecAnnotation, err)
+		}
+
+		return nil
+	}
+
+	out, err := json.Marshal(current)
+	if err != nil {
+		return err
+	}
+
+	if meta.Annotations == nil {
+		meta.Annotations = map[string]string{}
+	}
+
+	meta.Annotations[OriginalSpecAnnotation] = string(out)
+	return json.Unmarshal(out, original)
+}
+
+// restoreOriginalSpec copies the saved spec into original and removes the annotation. Returns false if there is no saved spec
+func restoreOriginalSpec(meta *metav1.ObjectMeta, original interface{}) (bool, error) {
+	saved, ok := meta.Annotations[OriginalSpecAnnotation]
+	if !ok {
+		return false, nil
+	}
+
+	err := json.Unmarshal([]byte(saved), original)
+	if err != nil {
+		return false, fmt.Errorf("Error parsing annotation %s: %v", OriginalSpecAnnotation, err)
+	}
+
+	delete(meta.Annotations, OriginalSpecAnnotation)
+	return true, nil
+}
+
+// applyDevModeTemplate replaces image, command and args of the dev container, removes its probes and mounts
+// an emptyDir volume at the volume path
+func applyDevModeTemplate(template *k8sv1.PodTemplateSpec, options *devModeOptions) error {
+	if len(template.Spec.Containers) == 0 {
+		return errors.New("Pod template has no containers")
+	}
+
+	container := &template.Spec.Containers[0]
+	if options.ContainerName != "" {
+		container = nil
+
+		for index := range template.Spec.Containers {
+			if template.Spec.Containers[index].Name == options.ContainerName {
+				container = &template.Spec.Containers[index]
+				break
+			}
+		}
+
+		if container == nil {
+			return fmt.Errorf("Container %s not found in pod template", options.ContainerName)
+		}
+	}
+
+	if options.Image != "" {
+		container.Image = options.Image
+	}
+
+	// The original args usually don't fit a replaced command
+	if options.Command != nil {
+		container.Command = options.Command
+		container.Args = nil
+	}
+	if options.Args != nil {
+		container.Args = options.Args
+	}
+
+	// Probes would restart the container while the application is stopped or restarted during development
+	container.LivenessProbe = nil
+	container.ReadinessProbe = nil
+
+	if options.VolumePath != "" {
+		volumeMounts := []k8sv1.VolumeMount{}
+		for _, volumeMount := range container.VolumeMounts {
+			if volumeMount.MountPath != options.VolumePath && volumeMount.Name != devModeVolumeName {
+				volumeMounts = append(volumeMounts, volumeMount)
+			}
+		}
+
+		container.VolumeMounts = append(volumeMounts, k8sv1.VolumeMount{
+			Name:      devModeVolumeName,
+			MountPath: options.VolumePath,
+		})
+
+		template.Spec.Volumes = append(template.Spec.Volumes, k8sv1.Volume{
+			Name: devModeVolumeName,
+			VolumeSource: k8sv1.VolumeSource{
+				EmptyDir: &k8sv1.EmptyDirVolumeSource{},
+			},
+		})
+	}
+
+	if template.Annotations == nil {
+		template.Annotations = map[string]string{}
+	}
+
+	template.Annotations[DevModeAnnotation] = "true"
+	return nil
+}
@@ -0,0 +1,286 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	k8sv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+var testDevModeOptions = &devModeOptions{
+	ContainerName: "app",
+	Image:         "node:dev",
+	Command:       []string{"sleep"},
+	Args:          []string{"infinity"},
+	VolumePath:    "/app/node_modules",
+}
+
+func TestDeploymentDevModeRoundTrip(t *testing.T) {
+	replicas := int32(3)
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app",
+			Namespace: "default",
+			Annotations: map[string]string{
+				"owner": "team",
+			},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": "app"},
+			},
+			Strategy: appsv1.DeploymentStrategy{
+				Type: appsv1.RecreateDeploymentStrategyType,
+			},
+			Template: getTestPodTemplate(),
+		},
+	}
+
+	originalSpec := marshalTestSpec(t, deployment.Spec)
+
+	// Starting dev mode twice (e.g. during a reload) must keep the original spec
+	for i := 0; i < 2; i++ {
+		err := patchDeployment(deployment, testDevModeOptions)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		deployment = roundTripTestObject(t, deployment, &appsv1.Deployment{}).(*appsv1.Deployment)
+	}
+
+	if *deployment.Spec.Replicas != 1 {
+		t.Fatalf("Expected 1 replica in dev mode, got %d", *deployment.Spec.Replicas)
+	}
+	checkTestDevModeTemplate(t, &deployment.Spec.Template)
+
+	restored, err := restoreDeployment(deployment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored == false {
+		t.Fatalf("Expected deployment in dev mode to be restored")
+	}
+
+	if restoredSpec := marshalTestSpec(t, deployment.Spec); restoredSpec != originalSpec {
+		t.Fatalf("Expected restored spec\n%s\nto equal original spec\n%s", restoredSpec, originalSpec)
+	}
+	checkTestAnnotations(t, deployment.Annotations)
+
+	restored, err = restoreDeployment(deployment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored {
+		t.Fatalf("Expected deployment that is not in dev mode not to be restored")
+	}
+}
+
+func TestStatefulSetDevModeRoundTrip(t *testing.T) {
+	replicas := int32(2)
+	statefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "db",
+			Namespace: "default",
+			Annotations: map[string]string{
+				"owner": "team",
+			},
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas:    &replicas,
+			ServiceName: "db",
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": "app"},
+			},
+			Template: getTestPodTemplate(),
+			VolumeClaimTemplates: []k8sv1.PersistentVolumeClaim{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "data",
+					},
+					Spec: k8sv1.PersistentVolumeClaimSpec{
+						AccessModes: []k8sv1.PersistentVolumeAccessMode{k8sv1.ReadWriteOnce},
+						Resources: k8sv1.ResourceRequirements{
+							Requests: k8sv1.ResourceList{
+								k8sv1.ResourceStorage: resource.MustParse("1Gi"),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	originalSpec := marshalTestSpec(t, statefulSet.Spec)
+
+	for i := 0; i < 2; i++ {
+		err := patchStatefulSet(statefulSet, testDevModeOptions)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		statefulSet = roundTripTestObject(t, statefulSet, &appsv1.StatefulSet{}).(*appsv1.StatefulSet)
+	}
+
+	if *statefulSet.Spec.Replicas != 1 {
+		t.Fatalf("Expected 1 replica in dev mode, got %d", *statefulSet.Spec.Replicas)
+	}
+	checkTestDevModeTemplate(t, &statefulSet.Spec.Template)
+
+	restored, err := restoreStatefulSet(statefulSet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored == false {
+		t.Fatalf("Expected statefulset in dev mode to be restored")
+	}
+
+	if restoredSpec := marshalTestSpec(t, statefulSet.Spec); restoredSpec != originalSpec {
+		t.Fatalf("Expected restored spec\n%s\nto equal original spec\n%s", restoredSpec, originalSpec)
+	}
+	checkTestAnnotations(t, statefulSet.Annotations)
+}
+
+func TestApplyDevModeTemplateUnknownContainer(t *testing.T) {
+	template := getTestPodTemplate()
+
+	err := applyDevModeTemplate(&template, &devModeOptions{
+		ContainerName: "unknown",
+	})
+	if err == nil {
+		t.Fatalf("Expected an error for an unknown container")
+	}
+}
+
+func getTestPodTemplate() k8sv1.PodTemplateSpec {
+	return k8sv1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{"app": "app"},
+		},
+		Spec: k8sv1.PodSpec{
+			Containers: []k8sv1.Container{
+				{
+					Name:    "sidecar",
+					Image:   "nginx:1.15",
+					Command: []string{"nginx"},
+				},
+				{
+					Name:    "app",
+					Image:   "node:10",
+					Command: []string{"node"},
+					Args:    []string{"index.js"},
+					Env: []k8sv1.EnvVar{
+						{Name: "NODE_ENV", Value: "production"},
+					},
+					Resources: k8sv1.ResourceRequirements{
+						Limits: k8sv1.ResourceList{
+							k8sv1.ResourceCPU:    resource.MustParse("500m"),
+							k8sv1.ResourceMemory: resource.MustParse("512Mi"),
+						},
+					},
+					LivenessProbe: &k8sv1.Probe{
+						Handler: k8sv1.Handler{
+							HTTPGet: &k8sv1.HTTPGetAction{
+								Path: "/healthz",
+								Port: intstr.FromInt(3000),
+							},
+						},
+					},
+					ReadinessProbe: &k8sv1.Probe{
+						Handler: k8sv1.Handler{
+							TCPSocket: &k8sv1.TCPSocketAction{
+								Port: intstr.FromInt(3000),
+							},
+						},
+					},
+					VolumeMounts: []k8sv1.VolumeMount{
+						{Name: "config", MountPath: "/app/config"},
+					},
+				},
+			},
+			Volumes: []k8sv1.Volume{
+				{
+					Name: "config",
+					VolumeSource: k8sv1.VolumeSource{
+						ConfigMap: &k8sv1.ConfigMapVolumeSource{
+							LocalObjectReference: k8sv1.LocalObjectReference{Name: "app-config"},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func checkTestDevModeTemplate(t *testing.T, template *k8sv1.PodTemplateSpec) {
+	sidecar := template.Spec.Containers[0]
+	if sidecar.Image != "nginx:1.15" || len(sidecar.Command) != 1 {
+		t.Fatalf("Expected container sidecar to be unchanged, got %v", sidecar)
+	}
+
+	app := template.Spec.Containers[1]
+	if app.Image != "node:dev" {
+		t.Fatalf("Expected dev image node:dev, got %s", app.Image)
+	}
+	if len(app.Command) != 1 || app.Command[0] != "sleep" || len(app.Args) != 1 || app.Args[0] != "infinity" {
+		t.Fatalf("Expected command sleep infinity, got %v %v", app.Command, app.Args)
+	}
+	if app.LivenessProbe != nil || app.ReadinessProbe != nil {
+		t.Fatalf("Expected probes to be removed in dev mode")
+	}
+	if len(app.VolumeMounts) != 2 || app.VolumeMounts[1].MountPath != "/app/node_modules" {
+		t.Fatalf("Expected dev mode volume mount at /app/node_modules, got %v", app.VolumeMounts)
+	}
+
+	// Patching twice must not add the volume twice
+	volumes := 0
+	for _, volume := range template.Spec.Volumes {
+		if volume.Name == devModeVolumeName {
+			volumes++
+		}
+	}
+	if volumes != 1 {
+		t.Fatalf("Expected one dev mode volume, got %d", volumes)
+	}
+
+	if template.Annotations[DevModeAnnotation] != "true" {
+		t.Fatalf("Expected pod template annotation %s", DevModeAnnotation)
+	}
+}
+
+func checkTestAnnotations(t *testing.T, annotations map[string]string) {
+	if _, ok := annotations[OriginalSpecAnnotation]; ok {
+		t.Fatalf("Expected annotation %s to be removed", OriginalSpecAnnotation)
+	}
+	if annotations["owner"] != "team" {
+		t.Fatalf("Expected other annotations to be kept, got %v", annotations)
+	}
+}
+
+func marshalTestSpec(t *testing.T, spec interface{}) string {
+	out, err := json.Marshal(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(out)
+}
+
+// roundTripTestObject encodes and decodes the object like an update through the api server does
+func roundTripTestObject(t *testing.T, object, into interface{}) interface{} {
+	out, err := json.Marshal(object)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = json.Unmarshal(out, into)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return into
+}
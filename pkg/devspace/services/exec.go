@@ -0,0 +1,71 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/covexo/devspace/pkg/devspace/config/configutil"
+	"github.com/covexo/devspace/pkg/devspace/kubectl"
+	"github.com/covexo/devspace/pkg/util/log"
+	"github.com/covexo/devspace/pkg/util/stdinutil"
+	"k8s.io/client-go/kubernetes"
+	kubectlExec "k8s.io/client-go/util/exec"
+)
+
+// ExecOptions define how the command is executed
+type ExecOptions struct {
+	TerminalOptions
+
+	// TTY allocates a terminal for the command
+	TTY bool
+
+	// Stdin passes stdin to the command. Stdin is always passed if it is not a terminal (e.g. if input is piped into devspace)
+	Stdin bool
+}
+
+// ExecCommand executes the command in the selected container and returns the exit code of the command.
+// Nothing but the output of the command is written to stdout, so that the output can be piped
+func ExecCommand(client *kubernetes.Clientset, serviceNameOverride, containerNameOverride, labelSelectorOverride, namespaceOverride string, args []string, options *ExecOptions, pick bool, log log.Logger) (int, error) {
+	config := configutil.GetConfig()
+
+	service, namespace, labelSelector, err := getServiceNamespaceLabelSelector(serviceNameOverride, labelSelectorOverride, namespaceOverride)
+	if err != nil {
+		return 0, err
+	}
+
+	pod, err := selectPod(client, labelSelector, namespace, time.Second*120, pick, log)
+	if err != nil {
+		return 0, fmt.Errorf("Error executing command: Cannot find running pod: %v", err)
+	}
+
+	// Get container name
+	containerName := containerNameOverride
+	if containerName == "" {
+		if service != nil && service.ContainerName != nil {
+			containerName = *service.ContainerName
+		} else if config.DevSpace.Terminal != nil && config.DevSpace.Terminal.ContainerName != nil {
+			containerName = *config.DevSpace.Terminal.ContainerName
+		}
+	}
+
+	containerName = selectContainer(pod, containerName, pick, log)
+	command := wrapTerminalCommand(args, &options.TerminalOptions)
+
+	var stdin io.Reader
+	if options.Stdin || options.TTY || stdinutil.IsTerminal() == false {
+		stdin = os.Stdin
+	}
+
+	err = kubectl.ExecStream(client, pod, containerName, command, options.TTY, stdin, os.Stdout, os.Stderr)
+	if err != nil {
+		if exitError, ok := err.(kubectlExec.CodeExitError); ok {
+			return exitError.Code, nil
+		}
+
+		return 0, fmt.Errorf("Unable to execute command: %v", err)
+	}
+
+	return 0, nil
+}
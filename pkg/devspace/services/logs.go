@@ -0,0 +1,247 @@
+package services
+
+import (
+	"bufio"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/covexo/devspace/pkg/devspace/config/configutil"
+	"github.com/covexo/devspace/pkg/devspace/kubectl"
+	"github.com/covexo/devspace/pkg/util/log"
+	"github.com/daviddengcn/go-colortext"
+	k8sv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// LogsOptions defines which logs should be printed
+type LogsOptions struct {
+	Follow   bool
+	Previous bool
+	Since    time.Duration
+	Tail     int64
+}
+
+var logColors = []ct.Color{
+	ct.Cyan,
+	ct.Magenta,
+	ct.Yellow,
+	ct.Blue,
+	ct.Green,
+	ct.Red,
+}
+
+// logPrinter prints log lines of multiple containers with a colored prefix
+type logPrinter struct {
+	printMutex sync.Mutex
+	colorIndex int
+
+	options *LogsOptions
+	streams map[string]bool
+	wg      sync.WaitGroup
+}
+
+// StartLogs prints the logs of all pods and containers that match the selected service or label selector
+func StartLogs(client *kubernetes.Clientset, serviceNameOverride, containerNameOverride, labelSelectorOverride, namespaceOverride string, options *LogsOptions, pick bool, log log.Logger) error {
+	_, namespace, labelSelector, err := getServiceNamespaceLabelSelector(serviceNameOverride, labelSelectorOverride, namespaceOverride)
+	if err != nil {
+		return err
+	}
+
+	if namespace == "" {
+		namespace, err = configutil.GetDefaultNamespace(configutil.GetConfig())
+		if err != nil {
+			return err
+		}
+	}
+
+	printer := &logPrinter{
+		options: options,
+		streams: make(map[string]bool),
+	}
+
+	// Only print the logs of the selected pod and container
+	if pick {
+		log.StartWait("Logs: Waiting for pods...")
+		pod, err := selectPod(client, labelSelector, namespace, time.Second*120, true, log)
+		log.StopWait()
+		if err != nil {
+			return fmt.Errorf("Cannot find running pod: %v", err)
+		}
+
+		containerName := selectContainer(pod, containerNameOverride, true, log)
+		if options.Previous {
+			return printer.printPreviousLogs(client, []k8sv1.Pod{*pod}, containerName, log)
+		}
+
+		return printer.streamLogs(client, pod, containerName, logColors[0])
+	}
+
+	podList, err := client.Core().Pods(namespace).List(metav1.ListOptions{
+		LabelSelector: labelSelector,
+	})
+	if err != nil {
+		return fmt.Errorf("Unable to list pods: %v", err)
+	}
+
+	if len(podList.Items) == 0 && options.Follow == false {
+		return fmt.Errorf("No pods found with selector %s in namespace %s", labelSelector, namespace)
+	}
+
+	if options.Previous {
+		return printer.printPreviousLogs(client, podList.Items, containerNameOverride, log)
+	}
+
+	printer.startStreams(client, podList.Items, containerNameOverride, log)
+	if options.Follow == false {
+		printer.wg.Wait()
+		return nil
+	}
+
+	// Pick up new pods as they appear
+	for {
+		time.Sleep(time.Second * 2)
+
+		podList, err := client.Core().Pods(namespace).List(metav1.ListOptions{
+			LabelSelector: labelSelector,
+		})
+		if err != nil {
+			log.Warnf("Unable to list pods: %v", err)
+			continue
+		}
+
+		printer.startStreams(client, podList.Items, containerNameOverride, log)
+	}
+}
+
+func (l *logPrinter) startStreams(client *kubernetes.Clientset, pods []k8sv1.Pod, containerName string, log log.Logger) {
+	sort.Slice(pods, func(i, j int) bool {
+		return pods[i].CreationTimestamp.Before(&pods[j].CreationTimestamp)
+	})
+
+	for _, pod := range pods {
+		for _, containerStatus := range pod.Status.ContainerStatuses {
+			if containerName != "" && containerStatus.Name != containerName {
+				continue
+			}
+			if containerStatus.State.Running == nil && containerStatus.State.Terminated == nil {
+				continue
+			}
+
+			// Every container instance is only streamed once, a restarted container gets a new key
+			key := fmt.Sprintf("%s/%s/%s/%d", pod.Namespace, pod.Name, containerStatus.Name, containerStatus.RestartCount)
+
+			l.printMutex.Lock()
+			if l.streams[key] {
+				l.printMutex.Unlock()
+				continue
+			}
+
+			l.streams[key] = true
+			color := logColors[l.colorIndex%len(logColors)]
+			l.colorIndex++
+			l.printMutex.Unlock()
+
+			l.wg.Add(1)
+			go func(pod k8sv1.Pod, container string) {
+				defer l.wg.Done()
+
+				err := l.streamLogs(client, &pod, container, color)
+				if err != nil {
+					log.Warnf("Error streaming logs of %s/%s: %v", pod.Name, container, err)
+				}
+			}(pod, containerStatus.Name)
+		}
+	}
+}
+
+func (l *logPrinter) streamLogs(client *kubernetes.Clientset, pod *k8sv1.Pod, container string, color ct.Color) error {
+	logOptions := &k8sv1.PodLogOptions{
+		Follow: l.options.Follow,
+	}
+
+	if l.options.Tail >= 0 {
+		tail := l.options.Tail
+		logOptions.TailLines = &tail
+	}
+	if l.options.Since > 0 {
+		sinceSeconds := int64(l.options.Since.Seconds())
+		logOptions.SinceSeconds = &sinceSeconds
+	}
+
+	reader, err := kubectl.LogStream(client, pod.Namespace, pod.Name, container, logOptions)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	prefix := "[" + pod.Name + "/" + container + "] "
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		l.printLine(prefix, scanner.Text(), color)
+	}
+
+	return scanner.Err()
+}
+
+func (l *logPrinter) printPreviousLogs(client *kubernetes.Clientset, pods []k8sv1.Pod, containerName string, log log.Logger) error {
+	found := false
+
+	for _, pod := range pods {
+		for _, containerStatus := range pod.Status.ContainerStatuses {
+			if containerName != "" && containerStatus.Name != containerName {
+				continue
+			}
+			if containerStatus.RestartCount == 0 && containerStatus.LastTerminationState.Terminated == nil {
+				continue
+			}
+
+			found = true
+			color := logColors[l.colorIndex%len(logColors)]
+			l.colorIndex++
+
+			var tail *int64
+			if l.options.Tail >= 0 {
+				tail = &l.options.Tail
+			}
+
+			logs, err := kubectl.Logs(client, pod.Namespace, pod.Name, containerStatus.Name, true, tail)
+			if err != nil {
+				log.Warnf("Unable to retrieve previous logs of %s/%s: %v", pod.Name, containerStatus.Name, err)
+				continue
+			}
+
+			reason := ""
+			if containerStatus.LastTerminationState.Terminated != nil {
+				reason = fmt.Sprintf(" (%s, exit code %d)", containerStatus.LastTerminationState.Terminated.Reason, containerStatus.LastTerminationState.Terminated.ExitCode)
+			}
+
+			log.Infof("Previous logs of %s/%s%s:", pod.Name, containerStatus.Name, reason)
+
+			prefix := "[" + pod.Name + "/" + containerStatus.Name + "] "
+			scanner := bufio.NewScanner(strings.NewReader(logs))
+			for scanner.Scan() {
+				l.printLine(prefix, scanner.Text(), color)
+			}
+		}
+	}
+
+	if found == false {
+		log.Info("No crashed or restarted containers found")
+	}
+
+	return nil
+}
+
+func (l *logPrinter) printLine(prefix, line string, color ct.Color) {
+	l.printMutex.Lock()
+	defer l.printMutex.Unlock()
+
+	log.WriteColored(prefix, color)
+	log.Write([]byte(line + "\n"))
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/covexo/devspace/pkg/devspace/kubectl"
+	"github.com/covexo/devspace/pkg/util/log"
+	"github.com/covexo/devspace/pkg/util/stdinutil"
+	k8sv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/client-go/kubernetes"
+)
+
+// selectPod waits for running pods and asks the user which pod to use if more than one pod is running or pick is true.
+// If stdin is not a terminal, the newest running pod is selected
+func selectPod(client *kubernetes.Clientset, labelSelector, namespace string, maxWaiting time.Duration, pick bool, log log.Logger) (*k8sv1.Pod, error) {
+	pods, err := kubectl.GetRunningPods(client, labelSelector, namespace, maxWaiting)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pods) == 1 && pick == false {
+		return pods[0], nil
+	}
+	if stdinutil.IsTerminal() == false {
+		if pick {
+			log.Warnf("Cannot pick pod interactively, because stdin is not a terminal. Using newest pod %s", pods[0].Name)
+		}
+
+		return pods[0], nil
+	}
+
+	// Make sure the wait message doesn't interfere with the question
+	log.StopWait()
+
+	options := make([]string, 0, len(pods))
+	for _, pod := range pods {
+		containers := make([]string, 0, len(pod.Spec.Containers))
+		for _, container := range pod.Spec.Containers {
+			containers = append(containers, container.Name)
+		}
+
+		options = append(options, fmt.Sprintf("%-45s %-10s %-6s %s", pod.Name, kubectl.GetPodStatus(pod), duration.HumanDuration(time.Since(pod.CreationTimestamp.Time)), strings.Join(containers, ",")))
+	}
+
+	fmt.Printf("\n     %-45s %-10s %-6s %s\n", "NAME", "STATUS", "AGE", "CONTAINERS")
+	selected := stdinutil.SelectFromList("Which pod do you want to use?", options)
+
+	return pods[selected], nil
+}
+
+// selectContainer returns the given container name or asks the user which container to use if no container name is given
+// and the pod has more than one container (or pick is true). If stdin is not a terminal, the first container is selected
+func selectContainer(pod *k8sv1.Pod, containerName string, pick bool, log log.Logger) string {
+	if containerName != "" && pick == false {
+		return containerName
+	}
+	if len(pod.Spec.Containers) == 1 {
+		return pod.Spec.Containers[0].Name
+	}
+	if stdinutil.IsTerminal() == false {
+		if containerName != "" {
+			return containerName
+		}
+
+		return pod.Spec.Containers[0].Name
+	}
+
+	options := make([]string, 0, len(pod.Spec.Containers))
+	for _, container := range pod.Spec.Containers {
+		options = append(options, fmt.Sprintf("%-30s %s", container.Name, container.Image))
+	}
+
+	fmt.Printf("\n     %-30s %s\n", "NAME", "IMAGE")
+	selected := stdinutil.SelectFromList("Which container of pod "+pod.Name+" do you want to use?", options)
+
+	return pod.Spec.Containers[selected].Name
+}
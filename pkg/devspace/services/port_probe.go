@@ -0,0 +1,193 @@
+package services
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/covexo/devspace/pkg/devspace/config/configutil"
+	"github.com/covexo/devspace/pkg/devspace/config/v1"
+	"github.com/covexo/devspace/pkg/util/log"
+)
+
+// PortProbe periodically checks if the local end of a port forwarding answers
+type PortProbe struct {
+	Name    string
+	Address string
+
+	probeType      string
+	path           string
+	expectedStatus int
+	interval       time.Duration
+	timeout        time.Duration
+
+	// healthy is the result of the last execution, which is only accessed by run
+	healthy *bool
+
+	stopChan chan struct{}
+	stopOnce sync.Once
+}
+
+// GetPortProbes creates the probes for all port mappings that have a probe configured
+func GetPortProbes() []*PortProbe {
+	config := configutil.GetConfig()
+	probes := []*PortProbe{}
+
+	if config.DevSpace == nil || config.DevSpace.Ports == nil {
+		return probes
+	}
+
+	for _, portForwarding := range *config.DevSpace.Ports {
+		if portForwarding.PortMappings == nil {
+			continue
+		}
+
+		name := "ports"
+		if portForwarding.Service != nil {
+			name = *portForwarding.Service
+		}
+
+		for _, portMapping := range *portForwarding.PortMappings {
+			if portMapping.Probe == nil || portMapping.LocalPort == nil {
+				continue
+			}
+
+			probes = append(probes, newPortProbe(name, portMapping))
+		}
+	}
+
+	return probes
+}
+
+func newPortProbe(name string, portMapping *v1.PortMapping) *PortProbe {
+	address := "127.0.0.1"
+	if portMapping.BindAddress != nil && *portMapping.BindAddress != "" && *portMapping.BindAddress != "0.0.0.0" {
+		address = *portMapping.BindAddress
+	}
+
+	probe := &PortProbe{
+		Name:           name + ":" + strconv.Itoa(*portMapping.LocalPort),
+		Address:        net.JoinHostPort(address, strconv.Itoa(*portMapping.LocalPort)),
+		probeType:      "tcp",
+		path:           "/",
+		expectedStatus: http.StatusOK,
+		interval:       10 * time.Second,
+		timeout:        2 * time.Second,
+		stopChan:       make(chan struct{}),
+	}
+
+	if portMapping.Probe.Type != nil && *portMapping.Probe.Type != "" {
+		probe.probeType = *portMapping.Probe.Type
+	} else if portMapping.Probe.Path != nil {
+		probe.probeType = "http"
+	}
+	if portMapping.Probe.Path != nil && *portMapping.Probe.Path != "" {
+		probe.path = *portMapping.Probe.Path
+	}
+	if portMapping.Probe.ExpectedStatus != nil {
+		probe.expectedStatus = *portMapping.Probe.ExpectedStatus
+	}
+	if portMapping.Probe.Interval != nil && *portMapping.Probe.Interval > 0 {
+		probe.interval = time.Duration(*portMapping.Probe.Interval) * time.Second
+	}
+	if portMapping.Probe.Timeout != nil && *portMapping.Probe.Timeout > 0 {
+		probe.timeout = time.Duration(*portMapping.Probe.Timeout) * time.Second
+	}
+
+	return probe
+}
+
+// StartPortProbes starts all configured port probes in the background
+func StartPortProbes(log log.Logger) []*PortProbe {
+	probes := GetPortProbes()
+	for _, probe := range probes {
+		go probe.run(log)
+	}
+
+	return probes
+}
+
+// Check executes the probe once and returns an error if the port does not answer as expected
+func (p *PortProbe) Check() error {
+	switch p.probeType {
+	case "tcp":
+		conn, err := net.DialTimeout("tcp", p.Address, p.timeout)
+		if err != nil {
+			return err
+		}
+
+		return conn.Close()
+	case "http":
+		client := &http.Client{
+			Timeout: p.timeout,
+		}
+
+		resp, err := client.Get("http://" + p.Address + p.path)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != p.expectedStatus {
+			return fmt.Errorf("Unexpected status code %d (expected %d)", resp.StatusCode, p.expectedStatus)
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("Unsupported probe type %s (supported: tcp, http)", p.probeType)
+}
+
+// Describe returns a short description of what the probe checks
+func (p *PortProbe) Describe() string {
+	if p.probeType == "http" {
+		return fmt.Sprintf("GET http://%s%s (expect %d)", p.Address, p.path, p.expectedStatus)
+	}
+
+	return p.probeType + " " + p.Address
+}
+
+// IsListening returns true if the local port accepts connections. The port is only forwarded while devspace up is running
+func (p *PortProbe) IsListening() bool {
+	conn, err := net.DialTimeout("tcp", p.Address, p.timeout)
+	if err != nil {
+		return false
+	}
+
+	conn.Close()
+	return true
+}
+
+// Stop stops the periodic probe execution
+func (p *PortProbe) Stop() {
+	p.stopOnce.Do(func() {
+		close(p.stopChan)
+	})
+}
+
+func (p *PortProbe) run(log log.Logger) {
+	for {
+		err := p.Check()
+		healthy := err == nil
+
+		changed := p.healthy == nil || *p.healthy != healthy
+		p.healthy = &healthy
+
+		if changed {
+			if healthy {
+				log.Donef("Probe %s is up (%s)", p.Name, p.Describe())
+			} else {
+				log.Warnf("Probe %s is down (%s): %v", p.Name, p.Describe(), err)
+			}
+		}
+
+		select {
+		case <-p.stopChan:
+			return
+		case <-time.After(p.interval):
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/covexo/devspace/pkg/devspace/config/configutil"
+	"github.com/covexo/devspace/pkg/devspace/config/v1"
+)
+
+func TestNewPortProbe(t *testing.T) {
+	testCases := []struct {
+		name                   string
+		probe                  *v1.PortProbeConfig
+		expectedType           string
+		expectedPath           string
+		expectedExpectedStatus int
+	}{
+		{
+			name:                   "tcp by default",
+			probe:                  &v1.PortProbeConfig{},
+			expectedType:           "tcp",
+			expectedPath:           "/",
+			expectedExpectedStatus: http.StatusOK,
+		},
+		{
+			name: "http if a path is configured",
+			probe: &v1.PortProbeConfig{
+				Path: configutil.String("/healthz"),
+			},
+			expectedType:           "http",
+			expectedPath:           "/healthz",
+			expectedExpectedStatus: http.StatusOK,
+		},
+		{
+			name: "explicit type",
+			probe: &v1.PortProbeConfig{
+				Type:           configutil.String("http"),
+				ExpectedStatus: configutil.Int(http.StatusNoContent),
+			},
+			expectedType:           "http",
+			expectedPath:           "/",
+			expectedExpectedStatus: http.StatusNoContent,
+		},
+	}
+
+	for _, testCase := range testCases {
+		probe := newPortProbe("api", &v1.PortMapping{
+			LocalPort: configutil.Int(3000),
+			Probe:     testCase.probe,
+		})
+
+		if probe.Name != "api:3000" {
+			t.Fatalf("Test case %s: expected name api:3000, got %s", testCase.name, probe.Name)
+		}
+		if probe.Address != "127.0.0.1:3000" {
+			t.Fatalf("Test case %s: expected address 127.0.0.1:3000, got %s", testCase.name, probe.Address)
+		}
+		if probe.probeType != testCase.expectedType {
+			t.Fatalf("Test case %s: expected type %s, got %s", testCase.name, testCase.expectedType, probe.probeType)
+		}
+		if probe.path != testCase.expectedPath {
+			t.Fatalf("Test case %s: expected path %s, got %s", testCase.name, testCase.expectedPath, probe.path)
+		}
+		if probe.expectedStatus != testCase.expectedExpectedStatus {
+			t.Fatalf("Test case %s: expected status %d, got %d", testCase.name, testCase.expectedExpectedStatus, probe.expectedStatus)
+		}
+	}
+}
+
+func TestPortProbeCheck(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/healthz" {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	serverPort := getTestServerPort(t, server)
+	closedPort := getClosedPort(t)
+
+	testCases := []struct {
+		name        string
+		port        int
+		probe       *v1.PortProbeConfig
+		expectedErr bool
+	}{
+		{
+			name:  "tcp open port",
+			port:  serverPort,
+			probe: &v1.PortProbeConfig{},
+		},
+		{
+			name:        "tcp closed port",
+			port:        closedPort,
+			probe:       &v1.PortProbeConfig{},
+			expectedErr: true,
+		},
+		{
+			name: "http expected status",
+			port: serverPort,
+			probe: &v1.PortProbeConfig{
+				Path:           configutil.String("/healthz"),
+				ExpectedStatus: configutil.Int(http.StatusNoContent),
+			},
+		},
+		{
+			name: "http unexpected status",
+			port: serverPort,
+			probe: &v1.PortProbeConfig{
+				Path: configutil.String("/healthz"),
+			},
+			expectedErr: true,
+		},
+		{
+			name: "http closed port",
+			port: closedPort,
+			probe: &v1.PortProbeConfig{
+				Type: configutil.String("http"),
+			},
+			expectedErr: true,
+		},
+		{
+			name: "unsupported type",
+			port: serverPort,
+			probe: &v1.PortProbeConfig{
+				Type: configutil.String("grpc"),
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		probe := newPortProbe("api", &v1.PortMapping{
+			LocalPort: configutil.Int(testCase.port),
+			Probe:     testCase.probe,
+		})
+
+		err := probe.Check()
+		if testCase.expectedErr && err == nil {
+			t.Fatalf("Test case %s: expected an error", testCase.name)
+		}
+		if testCase.expectedErr == false && err != nil {
+			t.Fatalf("Test case %s: %v", testCase.name, err)
+		}
+	}
+}
+
+func TestPortProbeIsListening(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	probe := newPortProbe("api", &v1.PortMapping{
+		LocalPort: configutil.Int(getTestServerPort(t, server)),
+		Probe:     &v1.PortProbeConfig{},
+	})
+	if probe.IsListening() == false {
+		t.Fatalf("Expected probe port to be listening")
+	}
+
+	probe = newPortProbe("api", &v1.PortMapping{
+		LocalPort: configutil.Int(getClosedPort(t)),
+		Probe:     &v1.PortProbeConfig{},
+	})
+	if probe.IsListening() {
+		t.Fatalf("Expected closed port not to be listening")
+	}
+}
+
+// getClosedPort returns a local port that nothing listens on
+func getClosedPort(t *testing.T) int {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	return port
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/covexo/devspace/pkg/devspace/config/configutil"
+	"github.com/covexo/devspace/pkg/devspace/config/v1"
+	"github.com/covexo/devspace/pkg/util/log"
+)
+
+// DefaultProxyDomain is the domain that is appended to the service names if no domain is configured
+const DefaultProxyDomain = "devspace.localhost"
+
+// DefaultProxyPort is the local port the proxy listens on if no port is configured. It differs from the ports that
+// are usually forwarded (e.g. 8080), so that the proxy doesn't collide with the port forwardings
+const DefaultProxyPort = 18080
+
+// Proxy is a local reverse proxy that routes requests by hostname to the forwarded ports
+type Proxy struct {
+	Address string
+	Routes  map[string]int
+
+	reverseProxies map[string]*httputil.ReverseProxy
+	server         *http.Server
+}
+
+// StartProxy starts the local hostname proxy for the configured port forwardings
+func StartProxy(log log.Logger) (*Proxy, error) {
+	config := configutil.GetConfig()
+	if config.DevSpace.Proxy == nil || (config.DevSpace.Proxy.Disabled != nil && *config.DevSpace.Proxy.Disabled == true) {
+		return nil, nil
+	}
+
+	domain := DefaultProxyDomain
+	if config.DevSpace.Proxy.Domain != nil && *config.DevSpace.Proxy.Domain != "" {
+		domain = strings.Trim(*config.DevSpace.Proxy.Domain, ".")
+	}
+
+	port := DefaultProxyPort
+	if config.DevSpace.Proxy.Port != nil {
+		port = *config.DevSpace.Proxy.Port
+	}
+
+	bindAddress := "127.0.0.1"
+	if config.DevSpace.Proxy.BindAddress != nil && *config.DevSpace.Proxy.BindAddress != "" {
+		bindAddress = *config.DevSpace.Proxy.BindAddress
+	}
+
+	var ports []*v1.PortForwardingConfig
+	if config.DevSpace.Ports != nil {
+		ports = *config.DevSpace.Ports
+	}
+
+	routes := getProxyRoutes(ports, domain)
+	if len(routes) == 0 {
+		log.Warn("Proxy: No port forwarding with a service found, therefore no hostname routes are available")
+	}
+
+	proxy := newProxy(net.JoinHostPort(bindAddress, strconv.Itoa(port)), routes)
+
+	listener, err := net.Listen("tcp", proxy.Address)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to listen on %s: %v", proxy.Address, err)
+	}
+
+	proxy.server = &http.Server{
+		Handler: proxy,
+	}
+
+	go func() {
+		err := proxy.server.Serve(listener)
+		if err != nil && err != http.ErrServerClosed {
+			log.Errorf("Proxy error: %v", err)
+		}
+	}()
+
+	hostnames := make([]string, 0, len(routes))
+	for hostname := range routes {
+		hostnames = append(hostnames, hostname)
+	}
+	sort.Strings(hostnames)
+
+	for _, hostname := range hostnames {
+		log.Donef("Proxy: http://%s:%d -> localhost:%d", hostname, port, routes[hostname])
+	}
+
+	return proxy, nil
+}
+
+func newProxy(address string, routes map[string]int) *Proxy {
+	proxy := &Proxy{
+		Address:        address,
+		Routes:         routes,
+		reverseProxies: make(map[string]*httputil.ReverseProxy, len(routes)),
+	}
+
+	// The reverse proxy also handles connection upgrades, so websockets are tunneled as well
+	for hostname, localPort := range routes {
+		proxy.reverseProxies[hostname] = httputil.NewSingleHostReverseProxy(&url.URL{
+			Scheme: "http",
+			Host:   "127.0.0.1:" + strconv.Itoa(localPort),
+		})
+	}
+
+	return proxy
+}
+
+// ServeHTTP implements the http.Handler interface and forwards the request to the local port of the requested hostname
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	hostname := r.Host
+	if host, _, err := net.SplitHostPort(hostname); err == nil {
+		hostname = host
+	}
+
+	reverseProxy, ok := p.reverseProxies[strings.ToLower(hostname)]
+	if ok == false {
+		http.Error(w, fmt.Sprintf("No devspace service found for hostname %s", hostname), http.StatusBadGateway)
+		return
+	}
+
+	reverseProxy.ServeHTTP(w, r)
+}
+
+// Close stops the proxy server
+func (p *Proxy) Close() error {
+	if p.server == nil {
+		return nil
+	}
+
+	return p.server.Close()
+}
+
+// getProxyRoutes maps <service>.<domain> to the first local port of the service and <localPort>.<service>.<domain> to every local port
+func getProxyRoutes(ports []*v1.PortForwardingConfig, domain string) map[string]int {
+	routes := make(map[string]int)
+
+	for _, portForwarding := range ports {
+		if portForwarding.Service == nil || *portForwarding.Service == "" || portForwarding.PortMappings == nil {
+			continue
+		}
+
+		serviceHost := strings.ToLower(*portForwarding.Service) + "." + domain
+		for _, portMapping := range *portForwarding.PortMappings {
+			if portMapping.LocalPort == nil {
+				continue
+			}
+
+			if _, ok := routes[serviceHost]; ok == false {
+				routes[serviceHost] = *portMapping.LocalPort
+			}
+
+			routes[strconv.Itoa(*portMapping.LocalPort)+"."+serviceHost] = *portMapping.LocalPort
+		}
+	}
+
+	return routes
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/covexo/devspace/pkg/devspace/config/configutil"
+	"github.com/covexo/devspace/pkg/devspace/config/v1"
+)
+
+func TestGetProxyRoutes(t *testing.T) {
+	ports := []*v1.PortForwardingConfig{
+		{
+			Service: configutil.String("API"),
+			PortMappings: &[]*v1.PortMapping{
+				{RemotePort: configutil.Int(9000)},
+				{LocalPort: configutil.Int(3000), RemotePort: configutil.Int(3000)},
+				{LocalPort: configutil.Int(3001), RemotePort: configutil.Int(3001)},
+			},
+		},
+		{
+			Service: configutil.String("web"),
+			PortMappings: &[]*v1.PortMapping{
+				{LocalPort: configutil.Int(8080), RemotePort: configutil.Int(80)},
+			},
+		},
+		{
+			// Port forwardings with a label selector have no service name and therefore no hostname
+			LabelSelector: &map[string]*string{
+				"app": configutil.String("db"),
+			},
+			PortMappings: &[]*v1.PortMapping{
+				{LocalPort: configutil.Int(5432), RemotePort: configutil.Int(5432)},
+			},
+		},
+		{
+			Service: configutil.String("empty"),
+		},
+	}
+
+	routes := getProxyRoutes(ports, "devspace.localhost")
+
+	expectedRoutes := map[string]int{
+		"api.devspace.localhost":      3000,
+		"3000.api.devspace.localhost": 3000,
+		"3001.api.devspace.localhost": 3001,
+		"web.devspace.localhost":      8080,
+		"8080.web.devspace.localhost": 8080,
+	}
+
+	if len(routes) != len(expectedRoutes) {
+		t.Fatalf("Expected routes %v, got %v", expectedRoutes, routes)
+	}
+	for hostname, localPort := range expectedRoutes {
+		if routes[hostname] != localPort {
+			t.Fatalf("Expected %s to route to port %d, got %d", hostname, localPort, routes[hostname])
+		}
+	}
+}
+
+func TestProxyServeHTTP(t *testing.T) {
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("api " + r.URL.Path))
+	}))
+	defer apiServer.Close()
+
+	webServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("web " + r.URL.Path))
+	}))
+	defer webServer.Close()
+
+	proxy := newProxy("127.0.0.1:0", map[string]int{
+		"api.devspace.localhost": getTestServerPort(t, apiServer),
+		"web.devspace.localhost": getTestServerPort(t, webServer),
+	})
+
+	testCases := []struct {
+		name           string
+		host           string
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "hostname with port",
+			host:           "api.devspace.localhost:18080",
+			path:           "/users",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "api /users",
+		},
+		{
+			name:           "hostname without port",
+			host:           "web.devspace.localhost",
+			path:           "/",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "web /",
+		},
+		{
+			name:           "uppercase hostname",
+			host:           "WEB.devspace.localhost",
+			path:           "/index.html",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "web /index.html",
+		},
+		{
+			name:           "unknown hostname",
+			host:           "db.devspace.localhost:18080",
+			path:           "/",
+			expectedStatus: http.StatusBadGateway,
+		},
+	}
+
+	for _, testCase := range testCases {
+		request := httptest.NewRequest("GET", "http://"+testCase.host+testCase.path, nil)
+		recorder := httptest.NewRecorder()
+
+		proxy.ServeHTTP(recorder, request)
+
+		if recorder.Code != testCase.expectedStatus {
+			t.Fatalf("Test case %s: expected status %d, got %d", testCase.name, testCase.expectedStatus, recorder.Code)
+		}
+
+		body, _ := ioutil.ReadAll(recorder.Body)
+		if testCase.expectedBody != "" && string(body) != testCase.expectedBody {
+			t.Fatalf("Test case %s: expected body %s, got %s", testCase.name, testCase.expectedBody, string(body))
+		}
+	}
+}
+
+func getTestServerPort(t *testing.T, server *httptest.Server) int {
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	port, err := strconv.Atoi(serverURL.Port())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return port
+}
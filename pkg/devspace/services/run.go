@@ -0,0 +1,161 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/covexo/devspace/pkg/devspace/config/configutil"
+	"github.com/covexo/devspace/pkg/devspace/kubectl"
+	"github.com/covexo/devspace/pkg/util/log"
+	"k8s.io/client-go/kubernetes"
+	kubectlExec "k8s.io/client-go/util/exec"
+)
+
+// RunCommand executes the named command from the config non-interactively and returns the exit code of the command
+func RunCommand(client *kubernetes.Clientset, name string, args []string, log log.Logger) (int, error) {
+	config := configutil.GetConfig()
+	if config.DevSpace.Commands == nil || (*config.DevSpace.Commands)[name] == nil {
+		return 0, fmt.Errorf("Command %s not found, available commands: %s", name, strings.Join(GetCommandNames(), ", "))
+	}
+
+	commandConfig := (*config.DevSpace.Commands)[name]
+	if commandConfig.Command == nil || len(*commandConfig.Command) == 0 {
+		return 0, fmt.Errorf("Command %s has no command specified", name)
+	}
+
+	command := []string{}
+	for _, cmd := range *commandConfig.Command {
+		command = append(command, *cmd)
+	}
+	command = append(command, args...)
+
+	serviceNameOverride := ""
+	if commandConfig.Service != nil {
+		serviceNameOverride = *commandConfig.Service
+	}
+
+	namespaceOverride := ""
+	if commandConfig.Namespace != nil {
+		namespaceOverride = *commandConfig.Namespace
+	}
+
+	labelSelectorOverride := ""
+	if commandConfig.LabelSelector != nil {
+		labels := make([]string, 0, len(*commandConfig.LabelSelector))
+		for key, value := range *commandConfig.LabelSelector {
+			labels = append(labels, key+"="+*value)
+		}
+
+		labelSelectorOverride = strings.Join(labels, ", ")
+	}
+
+	service, namespace, labelSelector, err := getServiceNamespaceLabelSelector(serviceNameOverride, labelSelectorOverride, namespaceOverride)
+	if err != nil {
+		return 0, err
+	}
+
+	log.StartWait("Run: Waiting for pods...")
+	pod, err := kubectl.GetNewestRunningPod(client, labelSelector, namespace, time.Second*120)
+	log.StopWait()
+	if err != nil {
+		return 0, fmt.Errorf("Error running command: Cannot find running pod: %v", err)
+	}
+
+	// Get container name
+	containerName := pod.Spec.Containers[0].Name
+	if commandConfig.ContainerName != nil {
+		containerName = *commandConfig.ContainerName
+	} else if service != nil && service.ContainerName != nil {
+		containerName = *service.ContainerName
+	} else if config.DevSpace.Terminal != nil && config.DevSpace.Terminal.ContainerName != nil {
+		containerName = *config.DevSpace.Terminal.ContainerName
+	}
+
+	workDir := ""
+	if commandConfig.WorkDir != nil {
+		workDir = *commandConfig.WorkDir
+	}
+
+	env := map[string]string{}
+	if commandConfig.Env != nil {
+		for key, value := range *commandConfig.Env {
+			// Variables without value (e.g. FOO: in the yaml) are skipped
+			if value != nil {
+				env[key] = *value
+			}
+		}
+	}
+
+	log.Infof("Running command %s in %s/%s", name, pod.Name, containerName)
+
+	err = kubectl.ExecStream(client, pod, containerName, wrapCommand(command, workDir, env, ""), false, nil, os.Stdout, os.Stderr)
+	if err != nil {
+		if exitError, ok := err.(kubectlExec.CodeExitError); ok {
+			return exitError.Code, nil
+		}
+
+		return 0, fmt.Errorf("Unable to run command %s: %v", name, err)
+	}
+
+	return 0, nil
+}
+
+// GetCommandNames returns the sorted names of all configured commands
+func GetCommandNames() []string {
+	config := configutil.GetConfig()
+	names := []string{}
+
+	if config.DevSpace.Commands != nil {
+		for name := range *config.DevSpace.Commands {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// wrapCommand wraps the command in a shell that changes into the working directory and exports the environment variables.
+// If user is set, the shell is started with su as the given user, which requires su in the container and a root container user
+func wrapCommand(command []string, workDir string, env map[string]string, user string) []string {
+	if workDir == "" && len(env) == 0 && user == "" {
+		return command
+	}
+
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	script := ""
+	if workDir != "" {
+		script += "cd " + shellQuote(workDir) + " && "
+	}
+	for _, key := range keys {
+		script += "export " + key + "=" + shellQuote(env[key]) + " && "
+	}
+
+	if user == "" {
+		script += "exec \"$@\""
+
+		return append([]string{"sh", "-c", script, "sh"}, command...)
+	}
+
+	// su doesn't pass positional arguments reliably, so the command is quoted into the script
+	quotedCommand := make([]string, 0, len(command))
+	for _, arg := range command {
+		quotedCommand = append(quotedCommand, shellQuote(arg))
+	}
+	script += "exec " + strings.Join(quotedCommand, " ")
+
+	return []string{"su", "-s", "/bin/sh", user, "-c", script}
+}
+
+// shellQuote quotes a string so that it can be safely used as a single word in a posix shell
+func shellQuote(value string) string {
+	return "'" + strings.Replace(value, "'", "'\"'\"'", -1) + "'"
+}
@@ -11,15 +11,25 @@ import (
 
 	"github.com/covexo/devspace/pkg/devspace/config/configutil"
 	"github.com/covexo/devspace/pkg/devspace/kubectl"
+	"github.com/covexo/devspace/pkg/util/asciicast"
 	"github.com/covexo/devspace/pkg/util/log"
+	k8sv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/httpstream"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/transport/spdy"
 	kubectlExec "k8s.io/client-go/util/exec"
 )
 
+// TerminalOptions override the working directory, environment and user of the terminal config
+type TerminalOptions struct {
+	WorkDir string
+	Env     map[string]string
+	User    string
+}
+
 // StartTerminal opens a new terminal
-func StartTerminal(client *kubernetes.Clientset, serviceNameOverride, containerNameOverride, labelSelectorOverride, namespaceOverride string, args []string, interrupt chan error, log log.Logger) error {
+func StartTerminal(client *kubernetes.Clientset, serviceNameOverride, containerNameOverride, labelSelectorOverride, namespaceOverride string, args []string, options *TerminalOptions, pick bool, recordFile string, interrupt chan error, log log.Logger) error {
 	var command []string
 	config := configutil.GetConfig()
 
@@ -39,53 +49,172 @@ func StartTerminal(client *kubernetes.Clientset, serviceNameOverride, containerN
 		}
 	}
 
+	command = wrapTerminalCommand(command, options)
+
 	service, namespace, labelSelector, err := getServiceNamespaceLabelSelector(serviceNameOverride, labelSelectorOverride, namespaceOverride)
 	if err != nil {
 		return err
 	}
 
-	// Get first running pod
-	log.StartWait("Terminal: Waiting for pods...")
-	pod, err := kubectl.GetNewestRunningPod(client, labelSelector, namespace, time.Second*120)
-	log.StopWait()
-	if err != nil {
-		return fmt.Errorf("Error starting terminal: Cannot find running pod: %v", err)
+	var recorder *asciicast.Recorder
+	if recordFile != "" {
+		recorder, err = asciicast.NewRecorder(recordFile)
+		if err != nil {
+			return fmt.Errorf("Unable to create recording %s: %v", recordFile, err)
+		}
+
+		defer func() {
+			recorder.Close()
+			log.Donef("Terminal session recorded to %s", recordFile)
+		}()
 	}
 
-	// Get container name
-	containerName := pod.Spec.Containers[0].Name
-	if containerNameOverride == "" {
-		if service != nil && service.ContainerName != nil {
-			containerName = *service.ContainerName
+	reconnect := config.DevSpace.Terminal != nil && config.DevSpace.Terminal.Reconnect != nil && *config.DevSpace.Terminal.Reconnect
+
+	for {
+		// Get first running pod
+		log.StartWait("Terminal: Waiting for pods...")
+		pod, err := selectPod(client, labelSelector, namespace, time.Second*120, pick, log)
+		log.StopWait()
+		if err != nil {
+			return fmt.Errorf("Error starting terminal: Cannot find running pod: %v", err)
+		}
+
+		// Get container name
+		containerName := ""
+		if containerNameOverride == "" {
+			if service != nil && service.ContainerName != nil {
+				containerName = *service.ContainerName
+			} else {
+				if config.DevSpace.Terminal.ContainerName != nil {
+					containerName = *config.DevSpace.Terminal.ContainerName
+				}
+			}
 		} else {
-			if config.DevSpace.Terminal.ContainerName != nil {
-				containerName = *config.DevSpace.Terminal.ContainerName
+			containerName = containerNameOverride
+		}
+
+		containerName = selectContainer(pod, containerName, pick, log)
+
+		wrapper, upgradeRoundTripper, err := getUpgraderWrapper()
+		if err != nil {
+			return err
+		}
+
+		terminalDone := make(chan error, 1)
+		go func() {
+			terminalDone <- kubectl.ExecStreamWithTransport(wrapper, upgradeRoundTripper, client, pod, containerName, command, true, os.Stdin, os.Stdout, os.Stderr, recorder)
+		}()
+
+		select {
+		case err = <-interrupt:
+			upgradeRoundTripper.Close()
+			return err
+		case terminalErr := <-terminalDone:
+			upgradeRoundTripper.Close()
+
+			// A terminal that was exited with exit code 0 was closed by the user
+			if reconnect && terminalErr != nil && waitForContainerGone(client, pod, containerName, containerGoneGracePeriod, log) {
+				log.Infof("Terminal: Pod %s/%s went away, will reopen the terminal as soon as a new pod is running", pod.Namespace, pod.Name)
+				continue
 			}
+
+			if terminalErr != nil {
+				if _, ok := terminalErr.(kubectlExec.CodeExitError); ok == false {
+					return fmt.Errorf("Unable to start terminal session: %v", terminalErr)
+				}
+			}
+
+			return nil
 		}
-	} else {
-		containerName = containerNameOverride
 	}
+}
 
-	wrapper, upgradeRoundTripper, err := getUpgraderWrapper()
-	if err != nil {
-		return err
-	}
+// wrapTerminalCommand applies the working directory, environment and user from the terminal config and the given options to the command.
+// Environment values in the config may reference local environment variables (e.g. ${GITHUB_TOKEN})
+func wrapTerminalCommand(command []string, options *TerminalOptions) []string {
+	config := configutil.GetConfig()
+	terminal := config.DevSpace.Terminal
 
-	go func() {
-		terminalErr := kubectl.ExecStreamWithTransport(wrapper, upgradeRoundTripper, client, pod, containerName, command, true, os.Stdin, os.Stdout, os.Stderr)
-		if terminalErr != nil {
-			if _, ok := terminalErr.(kubectlExec.CodeExitError); ok == false {
-				interrupt <- fmt.Errorf("Unable to start terminal session: %v", terminalErr)
-				return
+	workDir := ""
+	user := ""
+	env := map[string]string{}
+
+	if terminal != nil {
+		if terminal.WorkDir != nil {
+			workDir = *terminal.WorkDir
+		}
+		if terminal.User != nil {
+			user = *terminal.User
+		}
+		if terminal.Env != nil {
+			for key, value := range *terminal.Env {
+				if value != nil {
+					env[key] = os.ExpandEnv(*value)
+				}
 			}
 		}
+	}
+
+	if options != nil {
+		if options.WorkDir != "" {
+			workDir = options.WorkDir
+		}
+		if options.User != "" {
+			user = options.User
+		}
+		for key, value := range options.Env {
+			env[key] = value
+		}
+	}
+
+	return wrapCommand(command, workDir, env, user)
+}
+
+// containerGoneGracePeriod is the time the kubelet may take to report a crashed or restarted container in the pod status
+const containerGoneGracePeriod = 5 * time.Second
+
+// waitForContainerGone polls the pod status for the grace period, because the exec stream usually ends before the
+// kubelet reports the crashed container. Returns false if the container still runs after the grace period
+func waitForContainerGone(client *kubernetes.Clientset, pod *k8sv1.Pod, containerName string, gracePeriod time.Duration, log log.Logger) bool {
+	checkInterval := 500 * time.Millisecond
+
+	log.StartWait("Terminal: Checking if container " + containerName + " was restarted")
+	defer log.StopWait()
+
+	for {
+		if isContainerGone(client, pod, containerName) {
+			return true
+		}
+		if gracePeriod <= 0 {
+			return false
+		}
+
+		time.Sleep(checkInterval)
+		gracePeriod = gracePeriod - checkInterval
+	}
+}
 
-		interrupt <- nil
-	}()
+// isContainerGone checks if the pod was deleted or the container was restarted since the pod object was retrieved
+func isContainerGone(client *kubernetes.Clientset, pod *k8sv1.Pod, containerName string) bool {
+	currentPod, err := client.Core().Pods(pod.Namespace).Get(pod.Name, metav1.GetOptions{})
+	if err != nil || currentPod.DeletionTimestamp != nil {
+		return true
+	}
+
+	for _, oldStatus := range pod.Status.ContainerStatuses {
+		if oldStatus.Name != containerName {
+			continue
+		}
+
+		for _, currentStatus := range currentPod.Status.ContainerStatuses {
+			if currentStatus.Name == containerName {
+				return currentStatus.RestartCount != oldStatus.RestartCount || currentStatus.State.Running == nil
+			}
+		}
+	}
 
-	err = <-interrupt
-	upgradeRoundTripper.Close()
-	return err
+	return false
 }
 
 func getServiceNamespaceLabelSelector(serviceNameOverride, labelSelectorOverride, namespaceOverride string) (*v1.ServiceConfig, string, string, error) {
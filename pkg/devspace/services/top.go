@@ -0,0 +1,53 @@
+package services
+
+import (
+	"github.com/covexo/devspace/pkg/devspace/config/configutil"
+	"github.com/covexo/devspace/pkg/devspace/kubectl"
+	"k8s.io/client-go/kubernetes"
+)
+
+// GetResourceUsage returns the resource usage of the containers of the selected pods. If no service, label selector or
+// namespace is specified, the pods of all configured services are selected. The returned bool is false if the metrics api
+// is not available
+func GetResourceUsage(client *kubernetes.Clientset, serviceNameOverride, labelSelectorOverride, namespaceOverride string) ([]*kubectl.ContainerUsage, bool, error) {
+	config := configutil.GetConfig()
+	serviceNames := []string{serviceNameOverride}
+
+	if serviceNameOverride == "" && labelSelectorOverride == "" && namespaceOverride == "" && config.DevSpace.Services != nil && len(*config.DevSpace.Services) > 0 {
+		serviceNames = []string{}
+		for _, service := range *config.DevSpace.Services {
+			serviceNames = append(serviceNames, *service.Name)
+		}
+	}
+
+	usages := []*kubectl.ContainerUsage{}
+	metricsAvailable := true
+	selected := map[string]bool{}
+
+	for _, serviceName := range serviceNames {
+		_, namespace, labelSelector, err := getServiceNamespaceLabelSelector(serviceName, labelSelectorOverride, namespaceOverride)
+		if err != nil {
+			return nil, false, err
+		}
+
+		serviceUsages, serviceMetricsAvailable, err := kubectl.GetContainerUsage(client, labelSelector, namespace)
+		if err != nil {
+			return nil, false, err
+		}
+
+		metricsAvailable = metricsAvailable && serviceMetricsAvailable
+
+		// Services may select the same pods
+		for _, usage := range serviceUsages {
+			key := usage.Namespace + "/" + usage.Pod + "/" + usage.Container
+			if selected[key] {
+				continue
+			}
+
+			selected[key] = true
+			usages = append(usages, usage)
+		}
+	}
+
+	return usages, metricsAvailable, nil
+}
@@ -0,0 +1,186 @@
+package sync
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/covexo/devspace/pkg/devspace/kubectl"
+	"github.com/juju/errors"
+	k8sv1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ProgressFunc is called with the number of bytes transferred so far
+type ProgressFunc func(transferred int64)
+
+// uploadScript extracts the uploaded tar at the target path. If the target is an existing directory, the source is copied into it.
+// $1 is the target path, $2 the name of the source and $3 the type of the source (dir or file). Directories are extracted directly
+// into the target, a single file is extracted into a temporary directory first, so that it can be renamed
+const uploadScript = `target="$1";
+if [ -d "$target" ]; then target="$target/$2"; fi;
+if [ "$3" = "dir" ]; then
+	mkdir -p "$target" && tar xzpf - -C "$target";
+	exit $?;
+fi;
+parent=$(dirname "$target");
+mkdir -p "$parent" || exit 1;
+tmpDir=$(mktemp -d "$parent/.devspace-cp.XXXXXX") || exit 1;
+tar xzpf - -C "$tmpDir" && mv -f "$tmpDir/$2" "$target";
+exitCode=$?;
+rm -rf "$tmpDir";
+exit $exitCode`
+
+// downloadScript writes a tar of the file or directory $1 to stdout
+const downloadScript = `if [ ! -e "$1" ]; then echo "$1: No such file or directory" >&2; exit 1; fi;
+tar czf - -C "$(dirname "$1")" "$(basename "$1")"`
+
+// Upload copies a local file or directory to the container. If the container path is an existing directory,
+// the source is copied into it. Files and directories that match the exclude paths are not copied
+func Upload(client *kubernetes.Clientset, pod *k8sv1.Pod, container, localPath, containerPath string, excludePaths []string, progress ProgressFunc) error {
+	localPath, err := filepath.Abs(localPath)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	stat, err := os.Stat(localPath)
+	if err != nil {
+		return err
+	}
+
+	// Directories are packed without the directory itself, so that exclude paths are relative to the copied directory
+	name := filepath.Base(localPath)
+	sourceType := "dir"
+	watchPath := localPath
+	files := []*fileInformation{
+		{
+			Name:        "",
+			IsDirectory: true,
+		},
+	}
+
+	if stat.IsDir() == false {
+		sourceType = "file"
+		watchPath = filepath.Dir(localPath)
+		files = []*fileInformation{
+			{
+				Name: name,
+			},
+		}
+	}
+
+	config, err := newCopyConfig(watchPath, excludePaths)
+	if err != nil {
+		return err
+	}
+
+	tarFile, _, err := writeTar(files, config)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	defer os.Remove(tarFile)
+
+	file, err := os.Open(tarFile)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	defer file.Close()
+
+	stderr := &bytes.Buffer{}
+	err = kubectl.ExecStream(client, pod, container, []string{"sh", "-c", uploadScript, "sh", containerPath, name, sourceType}, false, &progressReader{reader: file, progress: progress}, &bytes.Buffer{}, stderr)
+	if err != nil {
+		return fmt.Errorf("Error extracting files in container: %v %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
+
+// Download copies a file or directory from the container to the local path. If the local path is an existing directory,
+// the source is copied into it. Files and directories that match the exclude paths are not copied
+func Download(client *kubernetes.Clientset, pod *k8sv1.Pod, container, containerPath, localPath string, excludePaths []string, progress ProgressFunc) error {
+	localPath, err := filepath.Abs(localPath)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	containerPath = path.Clean(containerPath)
+	name := path.Base(containerPath)
+
+	stat, err := os.Stat(localPath)
+	if err == nil && stat.IsDir() {
+		localPath = filepath.Join(localPath, name)
+	}
+
+	config, err := newCopyConfig(localPath, excludePaths)
+	if err != nil {
+		return err
+	}
+
+	reader, writer := io.Pipe()
+	stderr := &bytes.Buffer{}
+	execDone := make(chan error, 1)
+
+	go func() {
+		err := kubectl.ExecStream(client, pod, container, []string{"sh", "-c", downloadScript, "sh", containerPath}, false, nil, writer, stderr)
+		writer.CloseWithError(err)
+		execDone <- err
+	}()
+
+	// The entries in the tar start with the name of the copied file or directory, which is replaced by the local path
+	err = untarAll(&progressReader{reader: reader, progress: progress}, filepath.ToSlash(localPath), "/"+name, config)
+	reader.Close()
+
+	execErr := <-execDone
+	if execErr != nil {
+		return fmt.Errorf("Error reading files from container: %v %s", execErr, strings.TrimSpace(stderr.String()))
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	return nil
+}
+
+// newCopyConfig creates a sync config that is only used for the tar and untar functions
+func newCopyConfig(localPath string, excludePaths []string) (*SyncConfig, error) {
+	config := &SyncConfig{
+		WatchPath:           localPath,
+		ExcludePaths:        excludePaths,
+		fileIndex:           newFileIndex(),
+		silent:              true,
+		overwriteNewerFiles: true,
+	}
+
+	err := config.initIgnoreParsers()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	config.untarExcludeMatcher = config.ignoreMatcher
+	return config, nil
+}
+
+// progressReader reports the number of bytes read so far
+type progressReader struct {
+	reader   io.Reader
+	progress ProgressFunc
+	read     int64
+}
+
+// Read implements the io.Reader interface
+func (p *progressReader) Read(buf []byte) (int, error) {
+	n, err := p.reader.Read(buf)
+	p.read += int64(n)
+
+	if n > 0 && p.progress != nil {
+		p.progress(p.read)
+	}
+
+	return n, err
+}
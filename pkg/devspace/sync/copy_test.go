@@ -0,0 +1,80 @@
+package sync
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+)
+
+func TestCopyTarRoundtrip(t *testing.T) {
+	remote, local, outside := initTestDirs(t)
+	defer os.RemoveAll(remote)
+	defer os.RemoveAll(local)
+	defer os.RemoveAll(outside)
+
+	source := path.Join(remote, "dump")
+
+	os.MkdirAll(path.Join(source, "data"), 0755)
+	os.MkdirAll(path.Join(source, "ignoredFolder"), 0755)
+	ioutil.WriteFile(path.Join(source, "data", "testFile"), []byte(fileContents), 0666)
+	ioutil.WriteFile(path.Join(source, "data", "ignoredFile"), []byte(fileContents), 0666)
+	ioutil.WriteFile(path.Join(source, "ignoredFolder", "testFile"), []byte(fileContents), 0666)
+
+	// The tar contains the copied directory itself as for a download
+	config, err := newCopyConfig(remote, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tarFile, _, err := writeTar([]*fileInformation{
+		{
+			Name:        "dump",
+			IsDirectory: true,
+		},
+	}, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.Remove(tarFile)
+
+	// A newer local file has to be overwritten
+	target := path.Join(local, "copied")
+	os.MkdirAll(path.Join(target, "data"), 0755)
+	ioutil.WriteFile(path.Join(target, "data", "testFile"), []byte("newer"), 0666)
+	os.Chtimes(path.Join(target, "data", "testFile"), time.Now().Add(time.Hour), time.Now().Add(time.Hour))
+
+	config, err = newCopyConfig(target, []string{"ignoredFolder", "/data/ignoredFile"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := os.Open(tarFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer file.Close()
+
+	err = untarAll(file, target, "/dump", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := ioutil.ReadFile(path.Join(target, "data", "testFile"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != fileContents {
+		t.Fatalf("Expected %s to be overwritten, got content %s", path.Join(target, "data", "testFile"), string(content))
+	}
+
+	for _, excluded := range []string{path.Join(target, "data", "ignoredFile"), path.Join(target, "ignoredFolder")} {
+		_, err := os.Stat(excluded)
+		if err == nil {
+			t.Fatalf("Expected %s to be excluded", excluded)
+		}
+	}
+}
@@ -57,6 +57,10 @@ type SyncConfig struct {
 	silent   bool
 	stopOnce sync.Once
 
+	// Used for copying, where local files are always overwritten and excluded files are skipped when extracting
+	overwriteNewerFiles bool
+	untarExcludeMatcher gitignore.IgnoreParser
+
 	// Used for testing
 	testing   bool
 	errorChan chan error
@@ -58,10 +58,15 @@ func untarNext(tarReader *tar.Reader, destPath, prefix string, config *SyncConfi
 	outFileName := path.Join(destPath, relativePath)
 	baseName := path.Dir(outFileName)
 
+	// Skip files that are excluded from copying
+	if config.untarExcludeMatcher != nil && config.untarExcludeMatcher.MatchesPath(relativePath) {
+		return true, nil
+	}
+
 	// Check if newer file is there and then don't override?
 	stat, err := os.Stat(outFileName)
 
-	if err == nil {
+	if err == nil && config.overwriteNewerFiles == false {
 		if roundMtime(stat.ModTime()) > header.FileInfo().ModTime().Unix() {
 			// Update filemap otherwise we download and download again
 			config.fileIndex.fileMap[relativePath] = &fileInformation{
@@ -0,0 +1,207 @@
+package asciicast
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Header is the first line of an asciicast v2 file
+type Header struct {
+	Version   int               `json:"version"`
+	Width     uint16            `json:"width"`
+	Height    uint16            `json:"height"`
+	Timestamp int64             `json:"timestamp"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// Recorder writes terminal input, output and resize events in the asciicast v2 format
+// (see https://github.com/asciinema/asciinema/blob/develop/doc/asciicast-v2.md)
+type Recorder struct {
+	writer io.WriteCloser
+	start  time.Time
+
+	width         uint16
+	height        uint16
+	headerWritten bool
+
+	writeMutex sync.Mutex
+}
+
+// NewRecorder creates a new recorder that writes to the given file
+func NewRecorder(filename string) (*Recorder, error) {
+	file, err := os.Create(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return newRecorder(file), nil
+}
+
+func newRecorder(writer io.WriteCloser) *Recorder {
+	return &Recorder{
+		writer: writer,
+		start:  time.Now(),
+		width:  80,
+		height: 24,
+	}
+}
+
+// WrapOutput returns a writer that records everything written to w as output events
+func (r *Recorder) WrapOutput(w io.Writer) io.Writer {
+	return &recordingWriter{
+		writer: w,
+		events: &eventBuffer{
+			recorder:  r,
+			eventType: "o",
+		},
+	}
+}
+
+// WrapInput returns a reader that records everything read from reader as input events
+func (r *Recorder) WrapInput(reader io.Reader) io.Reader {
+	return &recordingReader{
+		reader: reader,
+		events: &eventBuffer{
+			recorder:  r,
+			eventType: "i",
+		},
+	}
+}
+
+// Resize records a terminal resize event. If no event was recorded yet, the size is used for the header instead
+func (r *Recorder) Resize(width, height uint16) {
+	r.writeMutex.Lock()
+	defer r.writeMutex.Unlock()
+
+	if r.headerWritten == false {
+		r.width = width
+		r.height = height
+		return
+	}
+
+	r.writeEvent("r", fmt.Sprintf("%dx%d", width, height))
+}
+
+// Close closes the underlying file
+func (r *Recorder) Close() error {
+	r.writeMutex.Lock()
+	defer r.writeMutex.Unlock()
+
+	if r.headerWritten == false {
+		r.writeHeader()
+	}
+
+	return r.writer.Close()
+}
+
+func (r *Recorder) record(eventType string, data []byte) {
+	r.writeMutex.Lock()
+	defer r.writeMutex.Unlock()
+
+	r.writeEvent(eventType, string(data))
+}
+
+// writeEvent writes a single event line, the write mutex has to be locked by the caller
+func (r *Recorder) writeEvent(eventType, data string) {
+	if r.headerWritten == false {
+		r.writeHeader()
+	}
+
+	line, err := json.Marshal([]interface{}{
+		float64(time.Since(r.start).Nanoseconds()/int64(time.Microsecond)) / 1000000,
+		eventType,
+		data,
+	})
+	if err != nil {
+		return
+	}
+
+	r.writer.Write(append(line, '\n'))
+}
+
+func (r *Recorder) writeHeader() {
+	r.headerWritten = true
+
+	header, err := json.Marshal(&Header{
+		Version:   2,
+		Width:     r.width,
+		Height:    r.height,
+		Timestamp: r.start.Unix(),
+		Env: map[string]string{
+			"SHELL": os.Getenv("SHELL"),
+			"TERM":  os.Getenv("TERM"),
+		},
+	})
+	if err != nil {
+		return
+	}
+
+	r.writer.Write(append(header, '\n'))
+}
+
+// eventBuffer holds back incomplete utf8 sequences, so that every recorded event contains valid utf8
+type eventBuffer struct {
+	recorder  *Recorder
+	eventType string
+	pending   []byte
+	mutex     sync.Mutex
+}
+
+func (e *eventBuffer) add(data []byte) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	data = append(e.pending, data...)
+	cut := len(data)
+
+	// Find the start of the last rune and check if it is complete
+	for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(data[i]) {
+			if utf8.FullRune(data[i:]) == false {
+				cut = i
+			}
+
+			break
+		}
+	}
+
+	e.pending = append([]byte{}, data[cut:]...)
+	if cut > 0 {
+		e.recorder.record(e.eventType, data[:cut])
+	}
+}
+
+type recordingWriter struct {
+	writer io.Writer
+	events *eventBuffer
+}
+
+// Write implements the io.Writer interface
+func (w *recordingWriter) Write(p []byte) (int, error) {
+	n, err := w.writer.Write(p)
+	if n > 0 {
+		w.events.add(p[:n])
+	}
+
+	return n, err
+}
+
+type recordingReader struct {
+	reader io.Reader
+	events *eventBuffer
+}
+
+// Read implements the io.Reader interface
+func (r *recordingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if n > 0 {
+		r.events.add(p[:n])
+	}
+
+	return n, err
+}
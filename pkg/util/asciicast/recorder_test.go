@@ -0,0 +1,88 @@
+package asciicast
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+type nopCloser struct {
+	*bytes.Buffer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
+
+func parseRecording(t *testing.T, recording string) (*Header, [][]interface{}) {
+	lines := strings.Split(strings.TrimSpace(recording), "\n")
+
+	header := &Header{}
+	err := json.Unmarshal([]byte(lines[0]), header)
+	if err != nil {
+		t.Fatalf("Error parsing header %s: %v", lines[0], err)
+	}
+
+	events := [][]interface{}{}
+	for _, line := range lines[1:] {
+		event := []interface{}{}
+		err := json.Unmarshal([]byte(line), &event)
+		if err != nil {
+			t.Fatalf("Error parsing event %s: %v", line, err)
+		}
+
+		events = append(events, event)
+	}
+
+	return header, events
+}
+
+func TestRecorder(t *testing.T) {
+	buffer := nopCloser{&bytes.Buffer{}}
+	recorder := newRecorder(buffer)
+	recorder.Resize(120, 40)
+
+	stdout := &bytes.Buffer{}
+	output := recorder.WrapOutput(stdout)
+	input := recorder.WrapInput(strings.NewReader("ls\r"))
+
+	output.Write([]byte("$ "))
+	ioutil.ReadAll(input)
+	recorder.Resize(100, 30)
+
+	// Write a multi byte character in two chunks
+	output.Write([]byte{0xc3})
+	output.Write([]byte{0xa4, '\n'})
+
+	err := recorder.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if stdout.String() != "$ ä\n" {
+		t.Fatalf("Unexpected output %q", stdout.String())
+	}
+
+	header, events := parseRecording(t, buffer.String())
+	if header.Version != 2 || header.Width != 120 || header.Height != 40 {
+		t.Fatalf("Unexpected header %#v", header)
+	}
+
+	expected := [][]string{
+		{"o", "$ "},
+		{"i", "ls\r"},
+		{"r", "100x30"},
+		{"o", "ä\n"},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, got %d: %v", len(expected), len(events), events)
+	}
+
+	for index, event := range events {
+		if event[1] != expected[index][0] || event[2] != expected[index][1] {
+			t.Fatalf("Unexpected event at index %d: %v (expected %v)", index, event, expected[index])
+		}
+	}
+}
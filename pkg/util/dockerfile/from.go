@@ -0,0 +1,59 @@
+package dockerfile
+
+import (
+	"io/ioutil"
+	"regexp"
+	"strings"
+)
+
+var findFromRegEx = regexp.MustCompile(`(?i)^(\s*FROM\s+(?:--\S+\s+)*)(\S+)(.*)$`)
+var findStageNameRegEx = regexp.MustCompile(`(?i)^\s+AS\s+(\S+)\s*$`)
+
+// GetBaseImages retrieves the images of all FROM statements of a dockerfile. References to previous
+// build stages and scratch are omitted
+func GetBaseImages(filename string) ([]string, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	images := []string{}
+	replaceBaseImages(data, func(image string) string {
+		images = append(images, image)
+		return image
+	})
+
+	return images, nil
+}
+
+// ReplaceBaseImages replaces the images of all FROM statements of a dockerfile with the image returned
+// by replace. References to previous build stages and scratch are not passed to replace
+func ReplaceBaseImages(data []byte, replace func(image string) string) []byte {
+	return []byte(replaceBaseImages(data, replace))
+}
+
+func replaceBaseImages(data []byte, replace func(image string) string) string {
+	lines := strings.Split(string(NormalizeNewlines(data)), "\n")
+	stages := map[string]bool{}
+
+	for i, line := range lines {
+		match := findFromRegEx.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+
+		image := match[2]
+		if stages[strings.ToLower(image)] == false && strings.ToLower(image) != "scratch" {
+			image = replace(image)
+		}
+
+		stageMatch := findStageNameRegEx.FindStringSubmatch(match[3])
+		if stageMatch != nil {
+			stages[strings.ToLower(stageMatch[1])] = true
+		}
+
+		lines[i] = match[1] + image + match[3]
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -39,7 +39,8 @@ func Directory(path string) (string, error) {
 	return fmt.Sprintf("%x", hash.Sum(nil)), nil
 }
 
-// DirectoryExcludes calculates a hash for a directory and excludes the submitted patterns
+// DirectoryExcludes calculates a hash for a directory and excludes the submitted patterns. The paths are hashed relative
+// to the directory, so the hash doesn't change if the directory is moved
 func DirectoryExcludes(srcPath string, excludePatterns []string) (string, error) {
 	hash := sha256.New()
 
@@ -84,10 +85,6 @@ func DirectoryExcludes(srcPath string, excludePatterns []string) (string, error)
 			return err
 		}
 
-		if include == "." && relFilePath != "." {
-			relFilePath = strings.Join([]string{".", relFilePath}, string(filepath.Separator))
-		}
-
 		skip := false
 
 		// If "include" is an exact match for the current file
@@ -95,6 +92,8 @@ func DirectoryExcludes(srcPath string, excludePatterns []string) (string, error)
 		// matches it, don't skip it. IOW, assume an explicit 'include'
 		// is asking for that file no matter what - which is true
 		// for some files, like .dockerignore and Dockerfile (sometimes)
+		//
+		// Like docker build, the path is matched without ./ prefix, otherwise patterns like *.log don't match
 		if relFilePath != "." {
 			skip, err = pm.Matches(relFilePath)
 			if err != nil {
@@ -139,9 +138,10 @@ func DirectoryExcludes(srcPath string, excludePatterns []string) (string, error)
 		}
 		seen[relFilePath] = true
 
+		hashPath := filepath.ToSlash(relFilePath)
 		if f.IsDir() {
 			// Path is enough
-			io.WriteString(hash, filePath)
+			io.WriteString(hash, hashPath)
 		} else {
 			// Check file change
 			checksum, err := hashFileCRC32(filePath, 0xedb88320)
@@ -149,7 +149,7 @@ func DirectoryExcludes(srcPath string, excludePatterns []string) (string, error)
 				return nil
 			}
 
-			io.WriteString(hash, filePath+";"+checksum)
+			io.WriteString(hash, hashPath+";"+checksum)
 		}
 
 		return nil
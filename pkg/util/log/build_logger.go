@@ -0,0 +1,227 @@
+package log
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// BuildLogger writes the output of an image build to a build log in addition to the underlying logger. Debug messages
+// (e.g. the snapshot messages of kaniko) are only written to the build log, so the console stays readable while the
+// build log contains the complete output
+type BuildLogger struct {
+	logger   Logger
+	buildLog io.Writer
+
+	writeMutex sync.Mutex
+}
+
+// NewBuildLogger creates a new logger that writes all messages and the raw build output to buildLog as well
+func NewBuildLogger(logger Logger, buildLog io.Writer) *BuildLogger {
+	return &BuildLogger{
+		logger:   logger,
+		buildLog: buildLog,
+	}
+}
+
+func (b *BuildLogger) writeToBuildLog(message string) {
+	b.writeMutex.Lock()
+	defer b.writeMutex.Unlock()
+
+	b.buildLog.Write([]byte(message + "\n"))
+}
+
+// Debug implements logger interface, the message is only written to the build log
+func (b *BuildLogger) Debug(args ...interface{}) {
+	b.writeToBuildLog(fmt.Sprint(args...))
+}
+
+// Debugf implements logger interface, the message is only written to the build log
+func (b *BuildLogger) Debugf(format string, args ...interface{}) {
+	b.writeToBuildLog(fmt.Sprintf(format, args...))
+}
+
+// Info implements logger interface
+func (b *BuildLogger) Info(args ...interface{}) {
+	b.writeToBuildLog(fmt.Sprint(args...))
+	b.logger.Info(args...)
+}
+
+// Infof implements logger interface
+func (b *BuildLogger) Infof(format string, args ...interface{}) {
+	b.writeToBuildLog(fmt.Sprintf(format, args...))
+	b.logger.Infof(format, args...)
+}
+
+// Warn implements logger interface
+func (b *BuildLogger) Warn(args ...interface{}) {
+	b.writeToBuildLog("Warning: " + fmt.Sprint(args...))
+	b.logger.Warn(args...)
+}
+
+// Warnf implements logger interface
+func (b *BuildLogger) Warnf(format string, args ...interface{}) {
+	b.writeToBuildLog("Warning: " + fmt.Sprintf(format, args...))
+	b.logger.Warnf(format, args...)
+}
+
+// Error implements logger interface
+func (b *BuildLogger) Error(args ...interface{}) {
+	b.writeToBuildLog("Error: " + fmt.Sprint(args...))
+	b.logger.Error(args...)
+}
+
+// Errorf implements logger interface
+func (b *BuildLogger) Errorf(format string, args ...interface{}) {
+	b.writeToBuildLog("Error: " + fmt.Sprintf(format, args...))
+	b.logger.Errorf(format, args...)
+}
+
+// Fatal implements logger interface
+func (b *BuildLogger) Fatal(args ...interface{}) {
+	b.writeToBuildLog("Fatal: " + fmt.Sprint(args...))
+	b.logger.Fatal(args...)
+}
+
+// Fatalf implements logger interface
+func (b *BuildLogger) Fatalf(format string, args ...interface{}) {
+	b.writeToBuildLog("Fatal: " + fmt.Sprintf(format, args...))
+	b.logger.Fatalf(format, args...)
+}
+
+// Panic implements logger interface
+func (b *BuildLogger) Panic(args ...interface{}) {
+	b.writeToBuildLog("Panic: " + fmt.Sprint(args...))
+	b.logger.Panic(args...)
+}
+
+// Panicf implements logger interface
+func (b *BuildLogger) Panicf(format string, args ...interface{}) {
+	b.writeToBuildLog("Panic: " + fmt.Sprintf(format, args...))
+	b.logger.Panicf(format, args...)
+}
+
+// Done implements logger interface
+func (b *BuildLogger) Done(args ...interface{}) {
+	b.writeToBuildLog(fmt.Sprint(args...))
+	b.logger.Done(args...)
+}
+
+// Donef implements logger interface
+func (b *BuildLogger) Donef(format string, args ...interface{}) {
+	b.writeToBuildLog(fmt.Sprintf(format, args...))
+	b.logger.Donef(format, args...)
+}
+
+// Fail implements logger interface
+func (b *BuildLogger) Fail(args ...interface{}) {
+	b.writeToBuildLog("Error: " + fmt.Sprint(args...))
+	b.logger.Fail(args...)
+}
+
+// Failf implements logger interface
+func (b *BuildLogger) Failf(format string, args ...interface{}) {
+	b.writeToBuildLog("Error: " + fmt.Sprintf(format, args...))
+	b.logger.Failf(format, args...)
+}
+
+// Print implements logger interface
+func (b *BuildLogger) Print(level logrus.Level, args ...interface{}) {
+	switch level {
+	case logrus.InfoLevel:
+		b.Info(args...)
+	case logrus.DebugLevel:
+		b.Debug(args...)
+	case logrus.WarnLevel:
+		b.Warn(args...)
+	case logrus.ErrorLevel:
+		b.Error(args...)
+	case logrus.PanicLevel:
+		b.Panic(args...)
+	case logrus.FatalLevel:
+		b.Fatal(args...)
+	}
+}
+
+// Printf implements logger interface
+func (b *BuildLogger) Printf(level logrus.Level, format string, args ...interface{}) {
+	switch level {
+	case logrus.InfoLevel:
+		b.Infof(format, args...)
+	case logrus.DebugLevel:
+		b.Debugf(format, args...)
+	case logrus.WarnLevel:
+		b.Warnf(format, args...)
+	case logrus.ErrorLevel:
+		b.Errorf(format, args...)
+	case logrus.PanicLevel:
+		b.Panicf(format, args...)
+	case logrus.FatalLevel:
+		b.Fatalf(format, args...)
+	}
+}
+
+// StartWait implements logger interface
+func (b *BuildLogger) StartWait(message string) {
+	b.logger.StartWait(message)
+}
+
+// StopWait implements logger interface
+func (b *BuildLogger) StopWait() {
+	b.logger.StopWait()
+}
+
+// PrintTable implements logger interface
+func (b *BuildLogger) PrintTable(header []string, values [][]string) {
+	b.logger.PrintTable(header, values)
+}
+
+// With implements logger interface
+func (b *BuildLogger) With(obj interface{}) *LoggerEntry {
+	return &LoggerEntry{
+		logger: b,
+		context: map[string]interface{}{
+			"context-1": obj,
+		},
+	}
+}
+
+// WithKey implements logger interface
+func (b *BuildLogger) WithKey(key string, obj interface{}) *LoggerEntry {
+	return &LoggerEntry{
+		logger: b,
+		context: map[string]interface{}{
+			key: obj,
+		},
+	}
+}
+
+// SetLevel implements logger interface
+func (b *BuildLogger) SetLevel(level logrus.Level) {
+	b.logger.SetLevel(level)
+}
+
+func (b *BuildLogger) printWithContext(fnType logFunctionType, context map[string]interface{}, args ...interface{}) {
+	b.writeToBuildLog(fmt.Sprint(args...))
+	if fnType != debugFn {
+		b.logger.printWithContext(fnType, context, args...)
+	}
+}
+
+func (b *BuildLogger) printWithContextf(fnType logFunctionType, context map[string]interface{}, format string, args ...interface{}) {
+	b.writeToBuildLog(fmt.Sprintf(format, args...))
+	if fnType != debugFn {
+		b.logger.printWithContextf(fnType, context, format, args...)
+	}
+}
+
+// Write implements logger interface, the raw output is written to the build log and the underlying logger
+func (b *BuildLogger) Write(message []byte) (int, error) {
+	b.writeMutex.Lock()
+	b.buildLog.Write(message)
+	b.writeMutex.Unlock()
+
+	return b.logger.Write(message)
+}
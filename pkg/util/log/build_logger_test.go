@@ -0,0 +1,33 @@
+package log
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBuildLogger(t *testing.T) {
+	console := &bytes.Buffer{}
+	buildLog := &bytes.Buffer{}
+
+	// The inner build logger records everything that is passed on to the underlying logger
+	logger := NewBuildLogger(NewBuildLogger(Discard, console), buildLog)
+
+	logger.Info("Building image")
+	logger.Debugf("Taking snapshot of %s", "/app")
+	logger.Warnf("Slow %s", "upload")
+	logger.Error("Build failed")
+	logger.Done("Done")
+	logger.WithKey("image", "api").Debug("Context hash")
+	logger.With("api").Info("Context uploaded")
+	logger.Write([]byte("Step 1/2 : FROM node\n"))
+
+	expectedConsole := "Building image\nWarning: Slow upload\nError: Build failed\nDone\nContext uploaded\nStep 1/2 : FROM node\n"
+	if console.String() != expectedConsole {
+		t.Fatalf("Expected console output\n%s\ngot\n%s", expectedConsole, console.String())
+	}
+
+	expectedBuildLog := "Building image\nTaking snapshot of /app\nWarning: Slow upload\nError: Build failed\nDone\nContext hash\nContext uploaded\nStep 1/2 : FROM node\n"
+	if buildLog.String() != expectedBuildLog {
+		t.Fatalf("Expected build log\n%s\ngot\n%s", expectedBuildLog, buildLog.String())
+	}
+}
@@ -0,0 +1,208 @@
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// PrefixLogger prefixes every message of the underlying logger, so that the output of parallel tasks (e.g. image builds)
+// stays readable. Wait messages are printed once instead of showing a loading text, because several loading texts would
+// overwrite each other
+type PrefixLogger struct {
+	prefix string
+	logger Logger
+
+	writeMutex  sync.Mutex
+	writeBuffer []byte
+	lastWait    string
+}
+
+// NewPrefixLogger creates a new logger that prefixes every message with [prefix]
+func NewPrefixLogger(prefix string, logger Logger) *PrefixLogger {
+	return &PrefixLogger{
+		prefix: "[" + prefix + "] ",
+		logger: logger,
+	}
+}
+
+func (p *PrefixLogger) prefixArgs(args []interface{}) []interface{} {
+	return []interface{}{p.prefix + fmt.Sprint(args...)}
+}
+
+// Debug implements logger interface
+func (p *PrefixLogger) Debug(args ...interface{}) {
+	p.logger.Debug(p.prefixArgs(args)...)
+}
+
+// Debugf implements logger interface
+func (p *PrefixLogger) Debugf(format string, args ...interface{}) {
+	p.logger.Debugf(p.prefix+format, args...)
+}
+
+// Info implements logger interface
+func (p *PrefixLogger) Info(args ...interface{}) {
+	p.logger.Info(p.prefixArgs(args)...)
+}
+
+// Infof implements logger interface
+func (p *PrefixLogger) Infof(format string, args ...interface{}) {
+	p.logger.Infof(p.prefix+format, args...)
+}
+
+// Warn implements logger interface
+func (p *PrefixLogger) Warn(args ...interface{}) {
+	p.logger.Warn(p.prefixArgs(args)...)
+}
+
+// Warnf implements logger interface
+func (p *PrefixLogger) Warnf(format string, args ...interface{}) {
+	p.logger.Warnf(p.prefix+format, args...)
+}
+
+// Error implements logger interface
+func (p *PrefixLogger) Error(args ...interface{}) {
+	p.logger.Error(p.prefixArgs(args)...)
+}
+
+// Errorf implements logger interface
+func (p *PrefixLogger) Errorf(format string, args ...interface{}) {
+	p.logger.Errorf(p.prefix+format, args...)
+}
+
+// Fatal implements logger interface
+func (p *PrefixLogger) Fatal(args ...interface{}) {
+	p.logger.Fatal(p.prefixArgs(args)...)
+}
+
+// Fatalf implements logger interface
+func (p *PrefixLogger) Fatalf(format string, args ...interface{}) {
+	p.logger.Fatalf(p.prefix+format, args...)
+}
+
+// Panic implements logger interface
+func (p *PrefixLogger) Panic(args ...interface{}) {
+	p.logger.Panic(p.prefixArgs(args)...)
+}
+
+// Panicf implements logger interface
+func (p *PrefixLogger) Panicf(format string, args ...interface{}) {
+	p.logger.Panicf(p.prefix+format, args...)
+}
+
+// Done implements logger interface
+func (p *PrefixLogger) Done(args ...interface{}) {
+	p.logger.Done(p.prefixArgs(args)...)
+}
+
+// Donef implements logger interface
+func (p *PrefixLogger) Donef(format string, args ...interface{}) {
+	p.logger.Donef(p.prefix+format, args...)
+}
+
+// Fail implements logger interface
+func (p *PrefixLogger) Fail(args ...interface{}) {
+	p.logger.Fail(p.prefixArgs(args)...)
+}
+
+// Failf implements logger interface
+func (p *PrefixLogger) Failf(format string, args ...interface{}) {
+	p.logger.Failf(p.prefix+format, args...)
+}
+
+// Print implements logger interface
+func (p *PrefixLogger) Print(level logrus.Level, args ...interface{}) {
+	p.logger.Print(level, p.prefixArgs(args)...)
+}
+
+// Printf implements logger interface
+func (p *PrefixLogger) Printf(level logrus.Level, format string, args ...interface{}) {
+	p.logger.Printf(level, p.prefix+format, args...)
+}
+
+// StartWait implements logger interface
+func (p *PrefixLogger) StartWait(message string) {
+	p.writeMutex.Lock()
+	defer p.writeMutex.Unlock()
+
+	if message != p.lastWait {
+		p.lastWait = message
+		p.logger.Info(p.prefix + message)
+	}
+}
+
+// StopWait implements logger interface
+func (p *PrefixLogger) StopWait() {
+	p.writeMutex.Lock()
+	defer p.writeMutex.Unlock()
+
+	p.lastWait = ""
+}
+
+// PrintTable implements logger interface
+func (p *PrefixLogger) PrintTable(header []string, values [][]string) {
+	p.logger.PrintTable(header, values)
+}
+
+// With implements logger interface
+func (p *PrefixLogger) With(obj interface{}) *LoggerEntry {
+	return &LoggerEntry{
+		logger: p,
+		context: map[string]interface{}{
+			"context-1": obj,
+		},
+	}
+}
+
+// WithKey implements logger interface
+func (p *PrefixLogger) WithKey(key string, obj interface{}) *LoggerEntry {
+	return &LoggerEntry{
+		logger: p,
+		context: map[string]interface{}{
+			key: obj,
+		},
+	}
+}
+
+// SetLevel implements logger interface
+func (p *PrefixLogger) SetLevel(level logrus.Level) {
+	p.logger.SetLevel(level)
+}
+
+func (p *PrefixLogger) printWithContext(fnType logFunctionType, context map[string]interface{}, args ...interface{}) {
+	p.logger.printWithContext(fnType, context, p.prefixArgs(args)...)
+}
+
+func (p *PrefixLogger) printWithContextf(fnType logFunctionType, context map[string]interface{}, format string, args ...interface{}) {
+	p.logger.printWithContextf(fnType, context, p.prefix+format, args...)
+}
+
+// Write implements logger interface. Only complete lines are written, each with the prefix. Carriage returns
+// (e.g. of progress bars) are treated as line breaks
+func (p *PrefixLogger) Write(message []byte) (int, error) {
+	p.writeMutex.Lock()
+	defer p.writeMutex.Unlock()
+
+	p.writeBuffer = append(p.writeBuffer, bytes.Replace(message, []byte("\r"), []byte("\n"), -1)...)
+
+	for {
+		index := bytes.IndexByte(p.writeBuffer, '\n')
+		if index == -1 {
+			break
+		}
+
+		line := p.writeBuffer[:index]
+		p.writeBuffer = p.writeBuffer[index+1:]
+
+		if len(bytes.TrimSpace(line)) > 0 {
+			_, err := p.logger.Write([]byte(p.prefix + string(line) + "\n"))
+			if err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	return len(message), nil
+}
@@ -0,0 +1,37 @@
+package stdinutil
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/docker/docker/pkg/term"
+)
+
+// IsTerminal returns true if stdin is an interactive terminal
+func IsTerminal() bool {
+	return term.IsTerminal(os.Stdin.Fd())
+}
+
+// SelectFromList prints the numbered options, asks the user to select one of them and returns the selected index
+func SelectFromList(question string, options []string) int {
+	for index, option := range options {
+		fmt.Printf("  %d) %s\n", index+1, option)
+	}
+	fmt.Print("\n")
+
+	for {
+		answer := GetFromStdin(&GetFromStdinParams{
+			Question:               question,
+			DefaultValue:           "1",
+			ValidationRegexPattern: "^[0-9]+$",
+		})
+
+		selected, err := strconv.Atoi(*answer)
+		if err == nil && selected >= 1 && selected <= len(options) {
+			return selected - 1
+		}
+
+		fmt.Printf("Please enter a number between 1 and %d\n", len(options))
+	}
+}